@@ -0,0 +1,229 @@
+package omap
+
+import "iter"
+
+// Cache is an ordered map with a capacity limit. Once the entries weigh more than
+// the capacity, it evicts the least recently used entries, or those chosen by its Policy.
+//
+// Get, TryGet and Set count as an access to the key; Peek, Has and iteration do not.
+// Without a Policy, accesses move the entries to the back, so iteration yields them
+// from the least to the most recently used. With a Policy, it yields them in
+// insertion order.
+//
+// A Cache must be created with NewLRU. Like Map, it is not safe for concurrent use.
+type Cache[K comparable, V any] struct {
+	m        Map[K, V]
+	policy   Policy[K] // nil for LRU, which keeps the entries of m in recency order
+	capacity int
+	weight   int
+	weigher  func(key K, value V) int
+	onEvict  func(key K, value V)
+}
+
+// CacheOptions configures a Cache. The zero value selects the defaults.
+type CacheOptions[K comparable, V any] struct {
+	// Policy decides which entry to evict. By default the least recently used entry
+	// is evicted. It must not be shared between caches.
+	Policy Policy[K]
+	// Weigher returns the non-negative weight of an entry, which counts against the
+	// capacity. It must return the same weight for the same entry. By default every
+	// entry weighs 1, so the capacity is the maximum number of entries.
+	Weigher func(key K, value V) int
+	// OnEvict is called with every entry that is evicted to make room, after it was
+	// removed. It is not called for Delete and Clear, and must not modify the cache.
+	OnEvict func(key K, value V)
+}
+
+// NewLRU creates and returns a new Cache with the given capacity, which evicts the
+// least recently used entries unless opts selects another policy. opts may be nil.
+// It panics if the capacity is not positive.
+func NewLRU[K comparable, V any](capacity int, opts *CacheOptions[K, V]) *Cache[K, V] {
+	if capacity <= 0 {
+		panic("omap: non-positive cache capacity")
+	}
+	if opts == nil {
+		opts = &CacheOptions[K, V]{}
+	}
+	c := &Cache[K, V]{
+		policy:   opts.Policy,
+		capacity: capacity,
+		weigher:  opts.Weigher,
+		onEvict:  opts.OnEvict,
+	}
+	return c
+}
+
+func (c *Cache[K, V]) weigh(key K, value V) int {
+	if c.weigher == nil {
+		return 1
+	}
+	return c.weigher(key, value)
+}
+
+// Set adds a key-value pair to the cache, or updates the value of an existing key.
+// A new key first evicts entries until it fits, so the policy cannot pick it as
+// the victim. An update evicts entries afterwards if the value grew. An entry that
+// weighs more than the capacity on its own is evicted right away, and the other
+// entries are kept.
+func (c *Cache[K, V]) Set(key K, value V) {
+	w := c.weigh(key, value)
+	if w > c.capacity {
+		if old, ok := c.m.LoadAndDelete(key); ok {
+			c.weight -= c.weigh(key, old)
+			if c.policy != nil {
+				c.policy.Remove(key)
+			}
+		}
+		if c.onEvict != nil {
+			c.onEvict(key, value)
+		}
+		return
+	}
+
+	if e := c.m.GetElement(key); e != nil {
+		c.weight += w - c.weigh(key, e.val)
+		e.val = value
+		c.access(e)
+	} else {
+		c.evict(w)
+		c.m.Set(key, value)
+		c.weight += w
+		if c.policy != nil {
+			c.policy.Insert(key)
+		}
+	}
+	c.evict(0)
+}
+
+// access records an access to the entry.
+func (c *Cache[K, V]) access(e *Element[K, V]) {
+	if c.policy == nil {
+		c.m.kl.move(e, c.m.kl.root.prev)
+	} else {
+		c.policy.Access(e.key)
+	}
+}
+
+// victim returns the key to evict next, or false if the cache is empty.
+func (c *Cache[K, V]) victim() (K, bool) {
+	if c.policy != nil {
+		return c.policy.Victim()
+	}
+	if e := c.m.Front(); e != nil {
+		return e.key, true
+	}
+	var zero K
+	return zero, false
+}
+
+// evict removes the victims of the policy until the cache has room for the extra weight.
+func (c *Cache[K, V]) evict(extra int) {
+	for c.weight+extra > c.capacity {
+		k, ok := c.victim()
+		if !ok {
+			return
+		}
+		v, loaded := c.m.LoadAndDelete(k)
+		if c.policy != nil {
+			c.policy.Remove(k)
+		}
+		if !loaded {
+			continue
+		}
+		c.weight -= c.weigh(k, v)
+		if c.onEvict != nil {
+			c.onEvict(k, v)
+		}
+	}
+}
+
+// Get retrieves the value associated with the given key, and records the access.
+func (c *Cache[K, V]) Get(key K) V {
+	v, _ := c.TryGet(key)
+	return v
+}
+
+// TryGet retrieves the value associated with the given key, and records the access.
+// It returns the value and true if the key exists, otherwise the zero value and false.
+func (c *Cache[K, V]) TryGet(key K) (V, bool) {
+	e := c.m.GetElement(key)
+	if e == nil {
+		var zero V
+		return zero, false
+	}
+	c.access(e)
+	return e.val, true
+}
+
+// Peek retrieves the value associated with the given key without recording an access.
+// It returns the value and true if the key exists, otherwise the zero value and false.
+func (c *Cache[K, V]) Peek(key K) (V, bool) {
+	return c.m.TryGet(key)
+}
+
+// Has checks if the cache contains the given key, without recording an access.
+func (c *Cache[K, V]) Has(key K) bool {
+	return c.m.Has(key)
+}
+
+// Delete removes the key-value pairs with the given keys.
+func (c *Cache[K, V]) Delete(keys ...K) {
+	for _, k := range keys {
+		if v, ok := c.m.LoadAndDelete(k); ok {
+			c.weight -= c.weigh(k, v)
+			if c.policy != nil {
+				c.policy.Remove(k)
+			}
+		}
+	}
+}
+
+// Clear removes all key-value pairs from the cache.
+func (c *Cache[K, V]) Clear() {
+	for e := c.m.Front(); e != nil && c.policy != nil; e = e.Next() {
+		c.policy.Remove(e.key)
+	}
+	c.m.Clear()
+	c.weight = 0
+}
+
+// Len returns the number of key-value pairs in the cache.
+func (c *Cache[K, V]) Len() int {
+	return c.m.Len()
+}
+
+// Weight returns the total weight of the entries, which is Len without a Weigher.
+func (c *Cache[K, V]) Weight() int {
+	return c.weight
+}
+
+// Capacity returns the maximum total weight of the entries.
+func (c *Cache[K, V]) Capacity() int {
+	return c.capacity
+}
+
+// Resize changes the capacity and evicts entries until the cache fits it.
+// It panics if the capacity is not positive.
+func (c *Cache[K, V]) Resize(capacity int) {
+	if capacity <= 0 {
+		panic("omap: non-positive cache capacity")
+	}
+	c.capacity = capacity
+	c.evict(0)
+}
+
+// All returns an iterator over the cache's entries, from the least to the most
+// recently used, or in insertion order with a Policy. It does not record accesses.
+func (c *Cache[K, V]) All() iter.Seq2[K, V] {
+	return c.m.All()
+}
+
+// Keys returns a slice of all keys in the cache, in the order of All.
+func (c *Cache[K, V]) Keys() []K {
+	return c.m.Keys()
+}
+
+// Values returns a slice of all values in the cache, in the order of their keys.
+func (c *Cache[K, V]) Values() []V {
+	return c.m.Values()
+}
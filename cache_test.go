@@ -0,0 +1,131 @@
+package omap
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestCache_LRU(t *testing.T) {
+	var evicted []string
+	c := NewLRU(3, &CacheOptions[string, int]{
+		OnEvict: func(k string, v int) {
+			evicted = append(evicted, k)
+		},
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Set("c", 3)
+	c.Get("a")     // a is now the most recently used
+	c.Peek("b")    // does not count
+	c.Set("d", 4)  // evicts b
+	c.Set("c", 30) // updates count as accesses
+	c.Set("e", 5)  // evicts a
+	c.Delete("d")  // not an eviction
+	c.Set("f", 6)  // fits again
+	if !slices.Equal(evicted, []string{"b", "a"}) {
+		t.Errorf("evicted %v, want [b a]", evicted)
+	}
+	if got := c.Keys(); !slices.Equal(got, []string{"c", "e", "f"}) {
+		t.Errorf("Keys() = %v, want [c e f]", got)
+	}
+	c.Get("e")
+	if got := c.Keys(); !slices.Equal(got, []string{"c", "f", "e"}) {
+		t.Errorf("Keys() = %v, want them in recency order [c f e]", got)
+	}
+	if c.Len() != 3 || c.Weight() != 3 || c.Capacity() != 3 || c.Has("a") || c.Get("c") != 30 {
+		t.Errorf("Len() = %d, Weight() = %d, Get(c) = %d", c.Len(), c.Weight(), c.Get("c"))
+	}
+	if _, ok := c.TryGet("b"); ok {
+		t.Error("TryGet(b) found an evicted key")
+	}
+
+	c.Resize(1) // evicts f and e, which are less recently used than c
+	if !slices.Equal(evicted, []string{"b", "a", "f", "e"}) || !slices.Equal(c.Keys(), []string{"c"}) {
+		t.Errorf("after Resize(1): evicted %v, Keys() = %v", evicted, c.Keys())
+	}
+	c.Clear()
+	c.Set("g", 7)
+	if c.Len() != 1 || c.Weight() != 1 || len(evicted) != 4 {
+		t.Errorf("after Clear(): Len() = %d, Weight() = %d", c.Len(), c.Weight())
+	}
+}
+
+func TestCache_Weigher(t *testing.T) {
+	var evicted []string
+	c := NewLRU(10, &CacheOptions[string, string]{
+		Weigher: func(k, v string) int { return len(v) },
+		OnEvict: func(k, v string) { evicted = append(evicted, k) },
+	})
+	c.Set("a", "1234")
+	c.Set("b", "1234")
+	c.Set("c", "12")
+	if c.Weight() != 10 || len(evicted) != 0 {
+		t.Fatalf("Weight() = %d, evicted %v", c.Weight(), evicted)
+	}
+
+	c.Set("c", "123") // growing an entry evicts as well
+	if c.Weight() != 7 || !slices.Equal(evicted, []string{"a"}) {
+		t.Errorf("Weight() = %d, evicted %v", c.Weight(), evicted)
+	}
+	c.Set("big", "12345678901") // heavier than the capacity, so only big itself is evicted
+	if c.Len() != 2 || c.Weight() != 7 || c.Has("big") || !slices.Equal(evicted, []string{"a", "big"}) {
+		t.Errorf("Len() = %d, Weight() = %d, evicted %v", c.Len(), c.Weight(), evicted)
+	}
+	c.Set("c", "12345678901") // an existing key that outgrows the capacity is evicted as well
+	if !slices.Equal(c.Keys(), []string{"b"}) || c.Weight() != 4 || !slices.Equal(evicted, []string{"a", "big", "c"}) {
+		t.Errorf("Keys() = %v, Weight() = %d, evicted %v", c.Keys(), c.Weight(), evicted)
+	}
+}
+
+func TestCache_Policy(t *testing.T) {
+	c := NewLRU(2, &CacheOptions[int, int]{Policy: NewFIFOPolicy[int]()})
+	c.Set(1, 1)
+	c.Set(2, 2)
+	c.Get(1) // does not matter for FIFO
+	c.Set(3, 3)
+	if got := c.Keys(); !slices.Equal(got, []int{2, 3}) {
+		t.Errorf("Keys() = %v, want [2 3]", got)
+	}
+}
+
+func TestCache_OrderedMap(t *testing.T) {
+	c := NewLRU[string, int](2, nil)
+	if err := UnmarshalJSON([]byte(`{"a":1,"b":2,"c":3}`), c); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.Keys(); !slices.Equal(got, []string{"b", "c"}) {
+		t.Errorf("Keys() = %v, want [b c]", got)
+	}
+	data, err := MarshalJSON(c)
+	if got := strings.TrimSpace(string(data)); err != nil || got != `{"b":2,"c":3}` {
+		t.Errorf("MarshalJSON() = %s, %v", got, err)
+	}
+}
+
+func TestNewLRU_Capacity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("NewLRU(0) did not panic")
+		}
+	}()
+	NewLRU[int, int](0, nil)
+}
+
+func BenchmarkCache_Set(b *testing.B) {
+	policies := map[string]func() Policy[int]{
+		"LRU":  func() Policy[int] { return nil },
+		"FIFO": NewFIFOPolicy[int],
+		"LFU":  NewLFUPolicy[int],
+		"2Q":   New2QPolicy[int],
+	}
+	for name, policy := range policies {
+		b.Run(name, func(b *testing.B) {
+			c := NewLRU(1000, &CacheOptions[int, int]{Policy: policy()})
+			for i := range b.N {
+				c.Set(i%2000, i)
+				c.Get(i % 100)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package omap
+
+// noCopy may be embedded into structs which must not be copied after the first use.
+// It makes go vet's copylocks check report such copies.
+type noCopy struct{}
+
+func (*noCopy) Lock()   {}
+func (*noCopy) Unlock() {}
+
+// Clone returns a shallow copy of the map with the same order.
+func (m *Map[K, V]) Clone() *Map[K, V] {
+	return m.CloneFunc(func(v V) V { return v })
+}
+
+// CloneFunc returns a copy of the map with the same order, copying each value with clone.
+func (m *Map[K, V]) CloneFunc(clone func(V) V) *Map[K, V] {
+	c := Make[K, V](len(m.kv))
+	m.walk(m.kl.root.next, false, func(e *Element[K, V]) bool {
+		c.kv[e.key] = c.kl.append(e.key, clone(e.val))
+		return true
+	})
+	return c
+}
@@ -0,0 +1,125 @@
+package omap
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+func TestMap_Clone(t *testing.T) {
+	m := newABC()
+	c := m.Clone()
+
+	if !slices.Equal(c.Keys(), m.Keys()) || !slices.Equal(c.Values(), m.Values()) {
+		t.Errorf("Clone() = %v %v, want %v %v", c.Keys(), c.Values(), m.Keys(), m.Values())
+	}
+
+	// The clone is independent of the original
+	c.Set("d", 4)
+	c.Delete("a")
+	c.MoveToFront("c")
+	m.Set("b", 20)
+	if want := []string{"a", "b", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("original Keys() = %v, want %v", m.Keys(), want)
+	}
+	if want := []string{"c", "b", "d"}; !slices.Equal(c.Keys(), want) {
+		t.Errorf("clone Keys() = %v, want %v", c.Keys(), want)
+	}
+	if c.Get("b") != 2 {
+		t.Errorf("clone Get(b) = %v, want 2", c.Get("b"))
+	}
+
+	var zero Map[string, int]
+	if c := zero.Clone(); c.Len() != 0 {
+		t.Errorf("Clone() of zero map has Len() = %d", c.Len())
+	}
+}
+
+func TestMap_CloneFunc(t *testing.T) {
+	m := New[string, []int]()
+	m.Set("a", []int{1, 2})
+	m.Set("b", []int{3})
+
+	c := m.CloneFunc(slices.Clone)
+	c.Get("a")[0] = 100
+
+	if got := m.Get("a"); got[0] != 1 {
+		t.Errorf("original value changed through deep clone: %v", got)
+	}
+	if want := []string{"a", "b"}; !slices.Equal(c.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", c.Keys(), want)
+	}
+}
+
+func TestMap_CopyDetection(t *testing.T) {
+	// copy by value without tripping go vet
+	copyOf := func(m *Map[string, int]) *Map[string, int] {
+		c := reflect.New(reflect.TypeFor[Map[string, int]]())
+		c.Elem().Set(reflect.ValueOf(m).Elem())
+		return c.Interface().(*Map[string, int])
+	}
+
+	tests := []struct {
+		name string
+		use  func(m *Map[string, int])
+	}{
+		{"Set", func(m *Map[string, int]) { m.Set("x", 1) }},
+		{"Set existing", func(m *Map[string, int]) { m.Set("a", 10) }},
+		{"TrySet existing", func(m *Map[string, int]) { m.TrySet("a", 10) }},
+		{"Compute", func(m *Map[string, int]) {
+			m.Compute("a", func(old int, exists bool) (int, bool) { return 10, true })
+		}},
+		{"ComputeIfPresent", func(m *Map[string, int]) {
+			m.ComputeIfPresent("a", func(old int) (int, bool) { return 10, true })
+		}},
+		{"SwapValue", func(m *Map[string, int]) { m.SwapValue("a", 10) }},
+		{"Delete", func(m *Map[string, int]) { m.Delete("a") }},
+		{"MoveToFront", func(m *Map[string, int]) { m.MoveToFront("c") }},
+		{"All", func(m *Map[string, int]) {
+			for range m.All() {
+			}
+		}},
+		{"Keys", func(m *Map[string, int]) { m.Keys() }},
+		{"Reverse", func(m *Map[string, int]) { m.Reverse() }},
+		{"At", func(m *Map[string, int]) { m.At(0) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newABC()
+			c := copyOf(m)
+			defer func() {
+				if r := recover(); r != "omap: use of a Map copied by value" {
+					t.Errorf("recover() = %v, want copy panic", r)
+				}
+				if want := []string{"a", "b", "c"}; !slices.Equal(m.Keys(), want) {
+					t.Errorf("original Keys() = %v, want %v", m.Keys(), want)
+				}
+				if v := m.Get("a"); v != 1 {
+					t.Errorf("original Get(a) = %d, want 1", v)
+				}
+			}()
+			tt.use(c)
+		})
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		m := New[string, int]()
+		c := copyOf(m)
+		defer func() {
+			if recover() == nil {
+				t.Error("Set on a copied empty map should panic")
+			}
+		}()
+		c.Set("x", 1)
+	})
+
+	t.Run("Zero", func(t *testing.T) {
+		var m Map[string, int]
+		c := copyOf(&m)
+		c.Set("x", 1)
+		if c.Len() != 1 || m.Len() != 0 {
+			t.Errorf("copy of an unused zero map should be usable")
+		}
+	})
+}
@@ -0,0 +1,203 @@
+package omap
+
+import (
+	"cmp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NaturalCompare compares two strings in natural order, treating runs of digits as numbers,
+// so that "item2" sorts before "item10". Strings that are equal in natural order,
+// such as "a01" and "a1", are ordered by strings.Compare. It can be passed to SortFunc.
+func NaturalCompare[S ~string](a, b S) int {
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		if isDigit(a[i]) && isDigit(b[j]) {
+			si, sj := i, j
+			for i < len(a) && isDigit(a[i]) {
+				i++
+			}
+			for j < len(b) && isDigit(b[j]) {
+				j++
+			}
+			if c := compareNumeric(string(a[si:i]), string(b[sj:j])); c != 0 {
+				return c
+			}
+			continue
+		}
+		if a[i] != b[j] {
+			return cmp.Compare(a[i], b[j])
+		}
+		i++
+		j++
+	}
+	if c := cmp.Compare(len(a)-i, len(b)-j); c != 0 {
+		return c
+	}
+	return strings.Compare(string(a), string(b))
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// compareNumeric compares two strings of decimal digits by their numeric value.
+func compareNumeric(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if c := cmp.Compare(len(a), len(b)); c != 0 {
+		return c
+	}
+	return strings.Compare(a, b)
+}
+
+// FoldCompare compares two strings case-insensitively under simple Unicode case folding.
+// Strings that differ only in case are ordered by strings.Compare. It can be passed to SortFunc.
+func FoldCompare[S ~string](a, b S) int {
+	s, t := string(a), string(b)
+	for s != "" && t != "" {
+		r1, n1 := utf8.DecodeRuneInString(s)
+		r2, n2 := utf8.DecodeRuneInString(t)
+		if r1 != r2 {
+			if c := cmp.Compare(foldRune(r1), foldRune(r2)); c != 0 {
+				return c
+			}
+		}
+		s, t = s[n1:], t[n2:]
+	}
+	if c := cmp.Compare(len(s), len(t)); c != 0 {
+		return c
+	}
+	return strings.Compare(string(a), string(b))
+}
+
+// foldRune returns the same rune for all runes that are equal under simple case
+// folding, such as 's', 'S' and 'ſ': the lower case of the smallest one.
+func foldRune(r rune) rune {
+	m := r
+	for f := unicode.SimpleFold(r); f != r; f = unicode.SimpleFold(f) {
+		m = min(m, f)
+	}
+	return unicode.ToLower(m)
+}
+
+// semver is a parsed semantic version. Numbers are kept as digit strings.
+type semver struct {
+	major, minor, patch string
+	pre                 string
+}
+
+// parseSemver parses a semantic version with an optional "v" prefix.
+// Missing minor and patch versions default to 0, and build metadata is ignored.
+func parseSemver(s string) (v semver, ok bool) {
+	s = strings.TrimPrefix(s, "v")
+	if i := strings.IndexByte(s, '+'); i >= 0 {
+		s = s[:i]
+	}
+	if i := strings.IndexByte(s, '-'); i >= 0 {
+		s, v.pre = s[:i], s[i+1:]
+		if v.pre == "" {
+			return v, false
+		}
+	}
+
+	parts := [3]string{"0", "0", "0"}
+	for i := range parts {
+		n := strings.IndexByte(s, '.')
+		if n < 0 {
+			n = len(s)
+		}
+		if n == 0 || strings.TrimLeft(s[:n], "0123456789") != "" {
+			return v, false
+		}
+		parts[i], s = s[:n], s[n:]
+		if s == "" {
+			break
+		}
+		if i == len(parts)-1 {
+			return v, false
+		}
+		s = s[1:]
+	}
+	v.major, v.minor, v.patch = parts[0], parts[1], parts[2]
+	return v, true
+}
+
+// SemverCompare compares two semantic versions such as "v1.9.0" and "1.10.0-rc.1"
+// following the precedence rules of Semantic Versioning 2.0.0. The "v" prefix is
+// optional. Invalid versions sort before valid ones, and versions of equal precedence
+// are ordered by strings.Compare. It can be passed to SortFunc.
+func SemverCompare[S ~string](a, b S) int {
+	v1, ok1 := parseSemver(string(a))
+	v2, ok2 := parseSemver(string(b))
+	switch {
+	case ok1 && !ok2:
+		return 1
+	case !ok1 && ok2:
+		return -1
+	case ok1 && ok2:
+		if c := cmp.Or(
+			compareNumeric(v1.major, v2.major),
+			compareNumeric(v1.minor, v2.minor),
+			compareNumeric(v1.patch, v2.patch),
+			comparePrerelease(v1.pre, v2.pre),
+		); c != 0 {
+			return c
+		}
+	}
+	return strings.Compare(string(a), string(b))
+}
+
+// comparePrerelease compares pre-release versions. A version without a pre-release
+// has higher precedence, numeric identifiers are compared numerically and have lower
+// precedence than alphanumeric ones, and a larger set of identifiers has higher precedence.
+func comparePrerelease(a, b string) int {
+	switch {
+	case a == b:
+		return 0
+	case a == "":
+		return 1
+	case b == "":
+		return -1
+	}
+
+	for a != "" && b != "" {
+		var x, y string
+		x, a, _ = strings.Cut(a, ".")
+		y, b, _ = strings.Cut(b, ".")
+		xNum := x != "" && strings.TrimLeft(x, "0123456789") == ""
+		yNum := y != "" && strings.TrimLeft(y, "0123456789") == ""
+		var c int
+		switch {
+		case xNum && yNum:
+			c = compareNumeric(x, y)
+		case xNum:
+			c = -1
+		case yNum:
+			c = 1
+		default:
+			c = strings.Compare(x, y)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
+
+// ThenBy combines comparison functions: ties of the first are broken by the
+// following ones in order. It can be passed to SortFunc.
+func ThenBy[T any](first func(a, b T) int, then ...func(a, b T) int) func(a, b T) int {
+	return func(a, b T) int {
+		if c := first(a, b); c != 0 {
+			return c
+		}
+		for _, compare := range then {
+			if c := compare(a, b); c != 0 {
+				return c
+			}
+		}
+		return 0
+	}
+}
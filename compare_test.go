@@ -0,0 +1,116 @@
+package omap
+
+import (
+	"cmp"
+	"slices"
+	"testing"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"item2", "item10", -1},
+		{"item10", "item2", 1},
+		{"item10", "item10", 0},
+		{"a1b2", "a1b10", -1},
+		{"a01", "a1", -1},
+		{"a", "a1", -1},
+		{"1.9", "1.10", -1},
+		{"x99999999999999999999999", "x100000000000000000000000", -1},
+		{"abc", "abd", -1},
+		{"", "a", -1},
+	}
+
+	for _, tt := range tests {
+		if got := NaturalCompare(tt.a, tt.b); got != tt.want {
+			t.Errorf("NaturalCompare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+		if got := NaturalCompare(tt.b, tt.a); got != -tt.want {
+			t.Errorf("NaturalCompare(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+		}
+	}
+}
+
+func TestSemverCompare(t *testing.T) {
+	// In ascending precedence, following the Semantic Versioning 2.0.0 examples
+	ordered := []string{
+		"invalid",
+		"v1.0.0-alpha",
+		"1.0.0-alpha.1",
+		"v1.0.0-alpha.beta",
+		"v1.0.0-beta",
+		"v1.0.0-beta.2",
+		"v1.0.0-beta.11",
+		"v1.0.0-rc.1",
+		"v1.0.0",
+		"v1.9",
+		"v1.9.1",
+		"v1.10.0",
+		"v2.0.0",
+	}
+
+	for i := range ordered {
+		for j := range ordered {
+			want := cmp.Compare(i, j)
+			if got := SemverCompare(ordered[i], ordered[j]); got != want {
+				t.Errorf("SemverCompare(%q, %q) = %d, want %d", ordered[i], ordered[j], got, want)
+			}
+		}
+	}
+
+	// Build metadata does not affect precedence
+	if got := SemverCompare("v1.0.0+a", "v1.0.1"); got != -1 {
+		t.Errorf("SemverCompare(v1.0.0+a, v1.0.1) = %d, want -1", got)
+	}
+	if got := SemverCompare("v1.0.0", "1.0.0"); got == 0 {
+		t.Error("distinct strings of equal precedence should not compare equal")
+	}
+
+	for _, s := range []string{"", "v", "1.", "1..2", "1.2.3.4", "1.x", "1.0.0-"} {
+		if _, ok := parseSemver(s); ok {
+			t.Errorf("parseSemver(%q) should fail", s)
+		}
+	}
+}
+
+func TestFoldCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"apple", "Banana", -1},
+		{"APPLE", "apple", -1},
+		{"apple", "apple", 0},
+		{"Äpfel", "äpfel", -1},
+		{"äpfel", "Zebra", 1},
+		{"ab", "A", 1},
+		{"ſ", "s", 1},
+		{"ſa", "Sb", -1},
+		{"\u212a", "k", 1},
+		{"a_", "aB", -1},
+	}
+
+	for _, tt := range tests {
+		if got := FoldCompare(tt.a, tt.b); got != tt.want {
+			t.Errorf("FoldCompare(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestThenBy(t *testing.T) {
+	m := New[string, int]()
+	for _, k := range []string{"b10", "a", "b2", "c1", "B"} {
+		m.Set(k, 0)
+	}
+
+	byLen := func(a, b string) int {
+		return cmp.Compare(len(a), len(b))
+	}
+	SortFunc(m, ThenBy(byLen, FoldCompare, NaturalCompare))
+
+	if want := []string{"a", "B", "b2", "c1", "b10"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+}
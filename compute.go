@@ -0,0 +1,105 @@
+package omap
+
+// Compute updates the value of the key based on its current value.
+// The function receives the current value and whether the key exists, and returns
+// the new value and whether to keep the key. Returning false deletes an existing key
+// or skips the insertion of a new one. An existing key keeps its position, while a
+// new key is appended to the end. Compute returns the resulting value and whether the
+// key exists afterwards.
+func (m *Map[K, V]) Compute(key K, fn func(old V, exists bool) (V, bool)) (V, bool) {
+	m.lazyInit()
+	m.kl.check()
+	e, exists := m.kv[key]
+	var old V
+	if exists {
+		old = e.val
+	}
+
+	value, keep := fn(old, exists)
+	switch {
+	case keep && exists:
+		e.val = value
+	case keep:
+		m.kv[key] = m.kl.append(key, value)
+	case exists:
+		m.kl.delete(e)
+		delete(m.kv, key)
+	}
+	if !keep {
+		var zero V
+		return zero, false
+	}
+	return value, true
+}
+
+// ComputeIfAbsent returns the value of the key if it exists.
+// Otherwise, it stores the value returned by fn and returns it.
+func (m *Map[K, V]) ComputeIfAbsent(key K, fn func() V) V {
+	m.lazyInit()
+	if e, exists := m.kv[key]; exists {
+		return e.val
+	}
+	value := fn()
+	m.kv[key] = m.kl.append(key, value)
+	return value
+}
+
+// ComputeIfPresent updates the value of the key if it exists.
+// The function receives the current value and returns the new value and whether to keep the key;
+// returning false deletes it. ComputeIfPresent returns the resulting value and whether the key
+// exists afterwards.
+func (m *Map[K, V]) ComputeIfPresent(key K, fn func(old V) (V, bool)) (V, bool) {
+	m.kl.check()
+	e, exists := m.kv[key]
+	if !exists {
+		var zero V
+		return zero, false
+	}
+
+	value, keep := fn(e.val)
+	if !keep {
+		m.kl.delete(e)
+		delete(m.kv, key)
+		var zero V
+		return zero, false
+	}
+	e.val = value
+	return value, true
+}
+
+// GetOrSet returns the existing value of the key if present.
+// Otherwise, it stores and returns the given value.
+// The loaded result is true if the value was loaded, false if stored.
+func (m *Map[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	m.lazyInit()
+	if e, exists := m.kv[key]; exists {
+		return e.val, true
+	}
+	m.kv[key] = m.kl.append(key, value)
+	return value, false
+}
+
+// SwapValue stores the value for the key and returns the previous value if any.
+// The loaded result reports whether the key was present. An existing key keeps its position.
+func (m *Map[K, V]) SwapValue(key K, value V) (previous V, loaded bool) {
+	m.lazyInit()
+	m.kl.check()
+	if e, exists := m.kv[key]; exists {
+		previous, e.val = e.val, value
+		return previous, true
+	}
+	m.kv[key] = m.kl.append(key, value)
+	return previous, false
+}
+
+// LoadAndDelete deletes the key and returns its previous value if any.
+// The loaded result reports whether the key was present.
+func (m *Map[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	e, exists := m.kv[key]
+	if !exists {
+		return value, false
+	}
+	m.kl.delete(e)
+	delete(m.kv, key)
+	return e.val, true
+}
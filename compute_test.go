@@ -0,0 +1,125 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMap_Compute(t *testing.T) {
+	m := newABC()
+
+	incr := func(old int, exists bool) (int, bool) {
+		return old + 10, true
+	}
+
+	if v, ok := m.Compute("a", incr); v != 11 || !ok {
+		t.Errorf("Compute(a) = (%v, %v), want (11, true)", v, ok)
+	}
+	if v, ok := m.Compute("d", incr); v != 10 || !ok {
+		t.Errorf("Compute(d) = (%v, %v), want (10, true)", v, ok)
+	}
+	if want := []string{"a", "b", "c", "d"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+
+	drop := func(old int, exists bool) (int, bool) {
+		return 0, false
+	}
+	if v, ok := m.Compute("b", drop); v != 0 || ok {
+		t.Errorf("Compute(b) = (%v, %v), want (0, false)", v, ok)
+	}
+	if v, ok := m.Compute("x", drop); v != 0 || ok {
+		t.Errorf("Compute(x) = (%v, %v), want (0, false)", v, ok)
+	}
+	if want := []string{"a", "c", "d"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+
+	// Works on zero map
+	var counts Map[string, int]
+	for _, w := range []string{"x", "y", "x"} {
+		counts.Compute(w, func(old int, _ bool) (int, bool) { return old + 1, true })
+	}
+	if counts.Get("x") != 2 || counts.Get("y") != 1 {
+		t.Errorf("counts = %v, want x:2 y:1", counts.Values())
+	}
+}
+
+func TestMap_ComputeIfAbsent(t *testing.T) {
+	m := newABC()
+
+	called := false
+	if v := m.ComputeIfAbsent("a", func() int { called = true; return 0 }); v != 1 || called {
+		t.Errorf("ComputeIfAbsent(a) = %v (called %v), want 1 without call", v, called)
+	}
+	if v := m.ComputeIfAbsent("d", func() int { return 4 }); v != 4 {
+		t.Errorf("ComputeIfAbsent(d) = %v, want 4", v)
+	}
+	if m.Back().Key() != "d" {
+		t.Errorf("Back() = %v, want d", m.Back().Key())
+	}
+}
+
+func TestMap_ComputeIfPresent(t *testing.T) {
+	m := newABC()
+
+	double := func(old int) (int, bool) { return old * 2, true }
+	if v, ok := m.ComputeIfPresent("b", double); v != 4 || !ok {
+		t.Errorf("ComputeIfPresent(b) = (%v, %v), want (4, true)", v, ok)
+	}
+	if v, ok := m.ComputeIfPresent("x", double); v != 0 || ok {
+		t.Errorf("ComputeIfPresent(x) = (%v, %v), want (0, false)", v, ok)
+	}
+	if m.Has("x") {
+		t.Error("ComputeIfPresent should not insert missing keys")
+	}
+
+	if _, ok := m.ComputeIfPresent("a", func(int) (int, bool) { return 0, false }); ok {
+		t.Error("ComputeIfPresent(a) with keep=false should report absence")
+	}
+	if want := []string{"b", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+}
+
+func TestMap_GetOrSet(t *testing.T) {
+	m := newABC()
+
+	if v, loaded := m.GetOrSet("a", 10); v != 1 || !loaded {
+		t.Errorf("GetOrSet(a) = (%v, %v), want (1, true)", v, loaded)
+	}
+	if v, loaded := m.GetOrSet("d", 4); v != 4 || loaded {
+		t.Errorf("GetOrSet(d) = (%v, %v), want (4, false)", v, loaded)
+	}
+	if m.Get("d") != 4 {
+		t.Errorf("Get(d) = %v, want 4", m.Get("d"))
+	}
+}
+
+func TestMap_SwapValue(t *testing.T) {
+	m := newABC()
+
+	if old, loaded := m.SwapValue("a", 10); old != 1 || !loaded {
+		t.Errorf("SwapValue(a) = (%v, %v), want (1, true)", old, loaded)
+	}
+	if old, loaded := m.SwapValue("d", 4); old != 0 || loaded {
+		t.Errorf("SwapValue(d) = (%v, %v), want (0, false)", old, loaded)
+	}
+	if want := []int{10, 2, 3, 4}; !slices.Equal(m.Values(), want) {
+		t.Errorf("Values() = %v, want %v", m.Values(), want)
+	}
+}
+
+func TestMap_LoadAndDelete(t *testing.T) {
+	m := newABC()
+
+	if v, loaded := m.LoadAndDelete("b"); v != 2 || !loaded {
+		t.Errorf("LoadAndDelete(b) = (%v, %v), want (2, true)", v, loaded)
+	}
+	if v, loaded := m.LoadAndDelete("b"); v != 0 || loaded {
+		t.Errorf("LoadAndDelete(b) again = (%v, %v), want (0, false)", v, loaded)
+	}
+	if want := []string{"a", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+}
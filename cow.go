@@ -0,0 +1,184 @@
+package omap
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+)
+
+// COWMap is a copy-on-write ordered map for read-mostly workloads.
+//
+// It publishes immutable versions of a Map through an atomic pointer. Readers load
+// the current version and never block, while writers copy the current version,
+// change the copy and publish it. Each write therefore takes O(n), so several changes
+// should be batched with Update.
+//
+// Iterators walk the version that is current when the iteration starts; changes made
+// during the iteration, even by the loop body, do not affect it.
+//
+// The zero value is an empty map ready to use. A COWMap must not be copied after first use.
+type COWMap[K comparable, V any] struct {
+	mu sync.Mutex // serializes writers
+	p  atomic.Pointer[Map[K, V]]
+}
+
+// NewCOW creates and returns a new COWMap instance.
+func NewCOW[K comparable, V any]() *COWMap[K, V] {
+	return &COWMap[K, V]{}
+}
+
+// load returns the current version, which must not be modified.
+func (c *COWMap[K, V]) load() *Map[K, V] {
+	if m := c.p.Load(); m != nil {
+		return m
+	}
+	return &Map[K, V]{}
+}
+
+// Update calls fn with a copy of the current version and publishes the copy as the
+// new version once fn returns. If fn panics, nothing is published. Updates are
+// serialized, so fn sees the changes of all previous updates. fn must not keep m.
+func (c *COWMap[K, V]) Update(fn func(m *Map[K, V])) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	m := c.load().Clone()
+	fn(m)
+	c.p.Store(m)
+}
+
+// Set adds a key-value pair to the map, or updates the value of an existing key.
+func (c *COWMap[K, V]) Set(key K, value V) {
+	c.Update(func(m *Map[K, V]) {
+		m.Set(key, value)
+	})
+}
+
+// TrySet adds a key-value pair to the map only if the key does not already exist.
+// It returns true if the key-value pair was added.
+func (c *COWMap[K, V]) TrySet(key K, value V) (added bool) {
+	c.Update(func(m *Map[K, V]) {
+		added = m.TrySet(key, value)
+	})
+	return added
+}
+
+// Delete removes the key-value pairs with the given keys.
+func (c *COWMap[K, V]) Delete(keys ...K) {
+	c.Update(func(m *Map[K, V]) {
+		m.Delete(keys...)
+	})
+}
+
+// Clear removes all key-value pairs from the map.
+func (c *COWMap[K, V]) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.p.Store(New[K, V]())
+}
+
+// Get retrieves the value associated with the given key.
+func (c *COWMap[K, V]) Get(key K) V {
+	return c.load().Get(key)
+}
+
+// TryGet retrieves the value associated with the given key.
+// It returns the value and true if the key exists, otherwise the zero value and false.
+func (c *COWMap[K, V]) TryGet(key K) (V, bool) {
+	return c.load().TryGet(key)
+}
+
+// Has checks if the map contains the given key.
+func (c *COWMap[K, V]) Has(key K) bool {
+	return c.load().Has(key)
+}
+
+// Len returns the number of key-value pairs in the map.
+func (c *COWMap[K, V]) Len() int {
+	return c.load().Len()
+}
+
+// Keys returns a slice of all keys in the map, in insertion order.
+func (c *COWMap[K, V]) Keys() []K {
+	return c.load().Keys()
+}
+
+// Values returns a slice of all values in the map, in the order of their keys.
+func (c *COWMap[K, V]) Values() []V {
+	return c.load().Values()
+}
+
+// First returns the first key-value pair of the map.
+// The ok result is false if the map is empty.
+func (c *COWMap[K, V]) First() (key K, value V, ok bool) {
+	return c.load().First()
+}
+
+// Last returns the last key-value pair of the map.
+// The ok result is false if the map is empty.
+func (c *COWMap[K, V]) Last() (key K, value V, ok bool) {
+	return c.load().Last()
+}
+
+// Snapshot returns a copy of the current version that may be modified freely.
+func (c *COWMap[K, V]) Snapshot() *Map[K, V] {
+	return c.load().Clone()
+}
+
+// walk yields the elements of the current version from the element that first returns.
+func (c *COWMap[K, V]) walk(first func(m *Map[K, V]) *Element[K, V], backward bool, yield func(e *Element[K, V]) bool) {
+	for e := first(c.load()); e != nil && yield(e); {
+		if backward {
+			e = e.Prev()
+		} else {
+			e = e.Next()
+		}
+	}
+}
+
+// All returns an iterator over the entries of the current version in insertion order.
+func (c *COWMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.walk((*Map[K, V]).Front, false, func(e *Element[K, V]) bool {
+			return yield(e.key, e.val)
+		})
+	}
+}
+
+// Backward returns an iterator over the entries of the current version in reverse insertion order.
+func (c *COWMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.walk((*Map[K, V]).Back, true, func(e *Element[K, V]) bool {
+			return yield(e.key, e.val)
+		})
+	}
+}
+
+// KeysSeq returns an iterator over the keys of the current version in insertion order.
+func (c *COWMap[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		c.walk((*Map[K, V]).Front, false, func(e *Element[K, V]) bool {
+			return yield(e.key)
+		})
+	}
+}
+
+// ValuesSeq returns an iterator over the values of the current version in insertion order.
+func (c *COWMap[K, V]) ValuesSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		c.walk((*Map[K, V]).Front, false, func(e *Element[K, V]) bool {
+			return yield(e.val)
+		})
+	}
+}
+
+// From returns an iterator over the entries of the current version starting at the given
+// key through the end of the map. The iteration is empty if the key does not exist.
+func (c *COWMap[K, V]) From(key K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		c.walk(func(m *Map[K, V]) *Element[K, V] {
+			return m.kv[key]
+		}, false, func(e *Element[K, V]) bool {
+			return yield(e.key, e.val)
+		})
+	}
+}
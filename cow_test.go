@@ -0,0 +1,160 @@
+package omap
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+)
+
+func TestCOWMap(t *testing.T) {
+	var c COWMap[string, int] // zero value is ready to use
+	if c.Len() != 0 || c.Has("a") || len(c.Keys()) != 0 {
+		t.Error("zero COWMap is not empty")
+	}
+	if _, _, ok := c.First(); ok {
+		t.Error("First() on an empty map = true")
+	}
+
+	c.Set("a", 1)
+	c.Update(func(m *Map[string, int]) {
+		m.Set("b", 2)
+		m.Set("c", 3)
+		m.MoveToFront("c")
+	})
+	if !c.TrySet("d", 4) || c.TrySet("a", 0) {
+		t.Error("TrySet() returned the wrong result")
+	}
+	c.Delete("b")
+	if want := []string{"c", "a", "d"}; !slices.Equal(c.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", c.Keys(), want)
+	}
+	if want := []int{3, 1, 4}; !slices.Equal(c.Values(), want) {
+		t.Errorf("Values() = %v, want %v", c.Values(), want)
+	}
+	if v, ok := c.TryGet("a"); !ok || v != 1 || c.Get("x") != 0 {
+		t.Errorf("TryGet(a) = %d, %v", v, ok)
+	}
+	if k, _, _ := c.Last(); k != "d" {
+		t.Errorf("Last() = %s, want d", k)
+	}
+	if got := collectKeys(c.Backward()); !slices.Equal(got, []string{"d", "a", "c"}) {
+		t.Errorf("Backward() = %v", got)
+	}
+	if got := collectKeys(c.From("a")); !slices.Equal(got, []string{"a", "d"}) {
+		t.Errorf("From(a) = %v", got)
+	}
+	if got := slices.Collect(c.ValuesSeq()); !slices.Equal(got, c.Values()) {
+		t.Errorf("ValuesSeq() = %v", got)
+	}
+
+	s := c.Snapshot()
+	s.Set("z", 26)
+	if c.Has("z") {
+		t.Error("Snapshot() shares entries with the map")
+	}
+
+	c.Clear()
+	if c.Len() != 0 {
+		t.Errorf("Len() after Clear() = %d", c.Len())
+	}
+}
+
+func TestCOWMap_Versions(t *testing.T) {
+	c := NewCOW[int, int]()
+	c.Set(1, 1)
+	c.Set(2, 2)
+
+	// iterations keep walking the version they started with
+	var keys []int
+	for k := range c.KeysSeq() {
+		keys = append(keys, k)
+		c.Delete(2)
+		c.Set(k+10, k)
+	}
+	if want := []int{1, 2}; !slices.Equal(keys, want) {
+		t.Errorf("KeysSeq() = %v, want %v", keys, want)
+	}
+	if want := []int{1, 11, 12}; !slices.Equal(c.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", c.Keys(), want)
+	}
+
+	// a failed update publishes nothing
+	func() {
+		defer func() { recover() }()
+		c.Update(func(m *Map[int, int]) {
+			m.Clear()
+			panic("fail")
+		})
+	}()
+	if c.Len() != 3 {
+		t.Errorf("Len() after a failed update = %d, want 3", c.Len())
+	}
+}
+
+func TestCOWMap_Concurrent(t *testing.T) {
+	c := NewCOW[int, int]()
+	var wg sync.WaitGroup
+	for w := range 4 {
+		wg.Go(func() {
+			for i := range 50 {
+				c.Update(func(m *Map[int, int]) {
+					m.Set(w*100+i, i)
+					m.Set(-1, m.Get(-1)+1)
+				})
+			}
+		})
+		wg.Go(func() {
+			for range 50 {
+				for k, v := range c.All() {
+					if k >= 0 && v != k%100 {
+						t.Errorf("entry %d: %d", k, v)
+					}
+				}
+				if _, err := json.Marshal(c); err != nil {
+					t.Error(err)
+				}
+			}
+		})
+	}
+	wg.Wait()
+	if c.Len() != 4*50+1 || c.Get(-1) != 4*50 {
+		t.Errorf("Len() = %d, Get(-1) = %d", c.Len(), c.Get(-1))
+	}
+}
+
+func TestCOWMap_Encoding(t *testing.T) {
+	c := NewCOW[string, int]()
+	if err := json.Unmarshal([]byte(`{"b":2,"a":1}`), c); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"b":2,"a":1}`; string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+	if err := json.Unmarshal([]byte(`{"a":`), c); err == nil || c.Len() != 2 {
+		t.Errorf("json.Unmarshal() of invalid JSON = %v, Len() = %d", err, c.Len())
+	}
+
+	c = NewCOW[string, int]()
+	if err := yaml.Unmarshal([]byte("b: 2\na: 1\n"), c); err != nil {
+		t.Fatal(err)
+	}
+	b, err = yaml.Marshal(c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "b: 2\na: 1\n"; string(b) != want {
+		t.Errorf("yaml.Marshal() = %q, want %q", b, want)
+	}
+
+	Sort(c)
+	if want := []string{"a", "b"}; !slices.Equal(c.Keys(), want) {
+		t.Errorf("Sort() keys = %v, want %v", c.Keys(), want)
+	}
+}
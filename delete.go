@@ -0,0 +1,88 @@
+package omap
+
+// deleteRun removes the n entries from first through last, which must be contiguous.
+func (m *Map[K, V]) deleteRun(first, last *Element[K, V], n int) {
+	for e := first; ; e = e.next {
+		delete(m.kv, e.key)
+		if e == last {
+			break
+		}
+	}
+	m.kl.deleteRun(first, last, n)
+}
+
+// DeleteFunc removes all entries for which del returns true, and returns the number of removed entries.
+// Consecutive matching entries are unlinked together. The function must not modify the map.
+func (m *Map[K, V]) DeleteFunc(del func(K, V) bool) int {
+	if len(m.kv) == 0 {
+		return 0
+	}
+	m.kl.check()
+
+	removed := 0
+	for e := m.kl.root.next; e != &m.kl.root; {
+		if !del(e.key, e.val) {
+			e = e.next
+			continue
+		}
+		first, last, n := e, e, 1
+		for e = e.next; e != &m.kl.root && del(e.key, e.val); e = e.next {
+			last = e
+			n++
+		}
+		m.deleteRun(first, last, n)
+		removed += n
+	}
+	return removed
+}
+
+// Retain keeps only the entries for which keep returns true, and returns the number of removed entries.
+// The function must not modify the map.
+func (m *Map[K, V]) Retain(keep func(K, V) bool) int {
+	return m.DeleteFunc(func(k K, v V) bool {
+		return !keep(k, v)
+	})
+}
+
+// DeleteRange removes the entries from the key from through the key to, inclusive,
+// and returns the number of removed entries. It removes nothing if either key does
+// not exist or to precedes from.
+func (m *Map[K, V]) DeleteRange(from, to K) int {
+	first, last, n := m.between(from, to)
+	if n > 0 {
+		m.deleteRun(first, last, n)
+	}
+	return n
+}
+
+// Truncate keeps the first n entries, removes the rest and returns the number of removed entries.
+func (m *Map[K, V]) Truncate(n int) int {
+	removed := len(m.kv) - max(n, 0)
+	if removed <= 0 {
+		return 0
+	}
+
+	last := m.kl.root.prev
+	first := last
+	for range removed - 1 {
+		first = first.prev
+	}
+	m.deleteRun(first, last, removed)
+	return removed
+}
+
+// KeepLast keeps the last n entries, removes the rest and returns the number of removed entries.
+func (m *Map[K, V]) KeepLast(n int) int {
+	removed := len(m.kv) - max(n, 0)
+	if removed <= 0 {
+		return 0
+	}
+
+	first := m.kl.root.next
+	last := first
+	for range removed - 1 {
+		last = last.next
+	}
+	m.deleteRun(first, last, removed)
+	return removed
+}
@@ -0,0 +1,160 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+)
+
+func newRange(n int) *Map[int, int] {
+	m := New[int, int]()
+	for i := range n {
+		m.Set(i, i)
+	}
+	return m
+}
+
+func TestMap_DeleteFunc(t *testing.T) {
+	m := newRange(10)
+
+	// Removes runs 0-2, 5 and 8-9
+	n := m.DeleteFunc(func(k, v int) bool {
+		return k < 3 || k == 5 || k > 7
+	})
+	if n != 6 {
+		t.Errorf("DeleteFunc() = %d, want 6", n)
+	}
+	if want := []int{3, 4, 6, 7}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+	if m.Len() != 4 || m.Has(0) || m.Has(9) {
+		t.Errorf("deleted keys are still present")
+	}
+
+	var back []int
+	for k := range m.Backward() {
+		back = append(back, k)
+	}
+	if want := []int{7, 6, 4, 3}; !slices.Equal(back, want) {
+		t.Errorf("Backward() = %v, want %v", back, want)
+	}
+
+	if n := m.DeleteFunc(func(int, int) bool { return true }); n != 4 || m.Len() != 0 {
+		t.Errorf("DeleteFunc(all) = %d, Len() = %d, want 4, 0", n, m.Len())
+	}
+
+	var zero Map[int, int]
+	if n := zero.DeleteFunc(func(int, int) bool { return true }); n != 0 {
+		t.Errorf("DeleteFunc() on zero map = %d, want 0", n)
+	}
+}
+
+func TestMap_Retain(t *testing.T) {
+	m := newRange(6)
+
+	if n := m.Retain(func(k, v int) bool { return v%2 == 0 }); n != 3 {
+		t.Errorf("Retain() = %d, want 3", n)
+	}
+	if want := []int{0, 2, 4}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+}
+
+func TestMap_DeleteRange(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to int
+		removed  int
+		want     []int
+	}{
+		{"Middle", 1, 3, 3, []int{0, 4}},
+		{"All", 0, 4, 5, []int{}},
+		{"Single", 2, 2, 1, []int{0, 1, 3, 4}},
+		{"Reversed bounds", 3, 1, 0, []int{0, 1, 2, 3, 4}},
+		{"Missing from", 9, 1, 0, []int{0, 1, 2, 3, 4}},
+		{"Missing to", 1, 9, 0, []int{0, 1, 2, 3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newRange(5)
+			if n := m.DeleteRange(tt.from, tt.to); n != tt.removed {
+				t.Errorf("DeleteRange(%d, %d) = %d, want %d", tt.from, tt.to, n, tt.removed)
+			}
+			if !slices.Equal(m.Keys(), tt.want) {
+				t.Errorf("Keys() = %v, want %v", m.Keys(), tt.want)
+			}
+			if m.Len() != len(tt.want) {
+				t.Errorf("Len() = %d, want %d", m.Len(), len(tt.want))
+			}
+		})
+	}
+}
+
+func TestMap_Truncate_KeepLast(t *testing.T) {
+	m := newRange(5)
+	if n := m.Truncate(2); n != 3 {
+		t.Errorf("Truncate(2) = %d, want 3", n)
+	}
+	if want := []int{0, 1}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() after Truncate = %v, want %v", m.Keys(), want)
+	}
+	if n := m.Truncate(5); n != 0 {
+		t.Errorf("Truncate(5) = %d, want 0", n)
+	}
+
+	m = newRange(5)
+	if n := m.KeepLast(2); n != 3 {
+		t.Errorf("KeepLast(2) = %d, want 3", n)
+	}
+	if want := []int{3, 4}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() after KeepLast = %v, want %v", m.Keys(), want)
+	}
+	if n := m.KeepLast(-1); n != 2 || m.Len() != 0 {
+		t.Errorf("KeepLast(-1) = %d, Len() = %d, want 2, 0", n, m.Len())
+	}
+}
+
+func TestMap_DeleteRun_Index(t *testing.T) {
+	m := newRange(100)
+	m.IndexOf(0) // build the index
+
+	m.DeleteRange(10, 19)
+	m.Truncate(80)
+	m.KeepLast(70)
+	m.DeleteFunc(func(k, v int) bool { return k%7 == 0 })
+
+	for i, k := range m.Keys() {
+		if got := m.IndexOf(k); got != i {
+			t.Fatalf("IndexOf(%d) = %d, want %d", k, got, i)
+		}
+		if got, _ := m.At(i); got != k {
+			t.Fatalf("At(%d) = %d, want %d", i, got, k)
+		}
+	}
+}
+
+func TestMap_DeleteRun_Iteration(t *testing.T) {
+	m := newRange(10)
+
+	var seen []int
+	for k := range m.All() {
+		seen = append(seen, k)
+		if k == 2 {
+			m.DeleteRange(3, 6)
+		}
+	}
+	if want := []int{0, 1, 2, 7, 8, 9}; !slices.Equal(seen, want) {
+		t.Errorf("seen = %v, want %v", seen, want)
+	}
+
+	seen = seen[:0]
+	for k := range m.Backward() {
+		seen = append(seen, k)
+		if k == 8 {
+			m.KeepLast(1)
+		}
+	}
+	if want := []int{9, 8}; !slices.Equal(seen, want) {
+		t.Errorf("seen backward = %v, want %v", seen, want)
+	}
+}
@@ -0,0 +1,231 @@
+package omap
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OpKind is the kind of change an Op describes.
+type OpKind int
+
+const (
+	// OpInsert adds a new key after Op.After.
+	OpInsert OpKind = iota
+	// OpDelete removes a key.
+	OpDelete
+	// OpUpdate changes the value of a key that keeps its position.
+	OpUpdate
+	// OpMove moves a key after Op.After and sets its value.
+	OpMove
+)
+
+// String returns the name of the kind.
+func (k OpKind) String() string {
+	switch k {
+	case OpInsert:
+		return "insert"
+	case OpDelete:
+		return "delete"
+	case OpUpdate:
+		return "update"
+	case OpMove:
+		return "move"
+	}
+	return "OpKind(" + fmt.Sprint(int(k)) + ")"
+}
+
+// Op is a single change to a map.
+type Op[K comparable, V any] struct {
+	Kind OpKind
+	Key  K
+	// Old is the previous value for OpDelete, OpUpdate and OpMove.
+	Old V
+	// Value is the new value for OpInsert, OpUpdate and OpMove.
+	Value V
+	// After is the key that the entry follows for OpInsert and OpMove,
+	// or nil to place the entry at the front.
+	After *K
+}
+
+// String formats the op as one or two lines of a unified-style diff.
+func (op Op[K, V]) String() string {
+	switch op.Kind {
+	case OpInsert:
+		return fmt.Sprintf("+ %v: %v (%s)", op.Key, op.Value, op.position())
+	case OpDelete:
+		return fmt.Sprintf("- %v: %v", op.Key, op.Old)
+	case OpUpdate:
+		return fmt.Sprintf("- %v: %v\n+ %v: %v", op.Key, op.Old, op.Key, op.Value)
+	case OpMove:
+		return fmt.Sprintf("- %v: %v\n+ %v: %v (moved, %s)", op.Key, op.Old, op.Key, op.Value, op.position())
+	}
+	return fmt.Sprintf("? %v: %v", op.Key, op.Kind)
+}
+
+func (op Op[K, V]) position() string {
+	if op.After == nil {
+		return "at front"
+	}
+	return fmt.Sprintf("after %v", *op.After)
+}
+
+// Patch is a sequence of changes that turns one map into another. It is created by Diff
+// and replayed by Apply.
+type Patch[K comparable, V any] []Op[K, V]
+
+// String formats the patch as a unified-style diff, one change per line, with deleted
+// entries prefixed by "-" and new entries prefixed by "+".
+func (p Patch[K, V]) String() string {
+	var b strings.Builder
+	for _, op := range p {
+		b.WriteString(op.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Diff returns the changes that turn a into b. Values are compared like Equal does.
+//
+// Keys that both maps have keep their position if they belong to a longest common
+// subsequence of the key orders, so the patch contains as few moves as possible.
+// Among such subsequences, the one with the most unchanged values is chosen.
+// The patch lists the deletes in the order of a, followed by the inserts, updates and
+// moves in the order of b. Each insert and move is anchored to the key that precedes
+// it in b. A nil map is treated as empty.
+func Diff[K comparable, V any](a, b *Map[K, V]) Patch[K, V] {
+	a, b = orEmpty(a), orEmpty(b)
+	var p Patch[K, V]
+	for k, v := range a.All() {
+		if _, ok := b.kv[k]; !ok {
+			p = append(p, Op[K, V]{Kind: OpDelete, Key: k, Old: v})
+		}
+	}
+
+	// the positions in a of the keys of b that a also has, in the order of b
+	pos := make(map[K]int, a.Len())
+	for e := a.Front(); e != nil; e = e.Next() {
+		pos[e.key] = len(pos)
+	}
+	var seq []int
+	var common []*Element[K, V]
+	for e := b.Front(); e != nil; e = e.Next() {
+		if i, ok := pos[e.key]; ok {
+			seq = append(seq, i)
+			common = append(common, e)
+		}
+	}
+	stay := keepers(seq, len(pos), func(i int) bool {
+		return valueEqual(a.kv[common[i].key].val, common[i].val)
+	})
+
+	i := 0
+	var after *K
+	for e := b.Front(); e != nil; e = e.Next() {
+		switch old, ok := a.kv[e.key]; {
+		case !ok:
+			p = append(p, Op[K, V]{Kind: OpInsert, Key: e.key, Value: e.val, After: after})
+		case !stay[i]:
+			p = append(p, Op[K, V]{Kind: OpMove, Key: e.key, Old: old.val, Value: e.val, After: after})
+			i++
+		default:
+			if !valueEqual(old.val, e.val) {
+				p = append(p, Op[K, V]{Kind: OpUpdate, Key: e.key, Old: old.val, Value: e.val})
+			}
+			i++
+		}
+		k := e.key
+		after = &k
+	}
+	return p
+}
+
+// keepers reports which elements of s keep their position. s holds distinct positions
+// in [0, n), and the result marks a longest increasing subsequence of s. Among those,
+// it prefers the elements for which unchanged reports true, since moving an entry whose
+// value changed anyway saves an update. It takes O(n log n) using a Fenwick tree that
+// holds, for prefixes of positions, the best subsequence ending there.
+func keepers(s []int, n int, unchanged func(i int) bool) []bool {
+	// the longest subsequence ending at s[i], and how many unchanged values it keeps
+	length := make([]int, len(s))
+	kept := make([]int, len(s))
+	better := func(i, j int) bool {
+		return j < 0 || length[i] > length[j] || length[i] == length[j] && kept[i] > kept[j]
+	}
+
+	prev := make([]int, len(s))
+	tree := make([]int, n+1) // 1 + the index into s, or 0 for none
+	best := -1
+	for i, p := range s {
+		prev[i] = -1
+		for t := p; t > 0; t -= t & -t {
+			if j := tree[t] - 1; j >= 0 && better(j, prev[i]) {
+				prev[i] = j
+			}
+		}
+
+		length[i], kept[i] = 1, 0
+		if unchanged(i) {
+			kept[i] = 1
+		}
+		if j := prev[i]; j >= 0 {
+			length[i] += length[j]
+			kept[i] += kept[j]
+		}
+
+		for t := p + 1; t <= n; t += t & -t {
+			if better(i, tree[t]-1) {
+				tree[t] = i + 1
+			}
+		}
+		if better(i, best) {
+			best = i
+		}
+	}
+
+	keep := make([]bool, len(s))
+	for i := best; i >= 0; i = prev[i] {
+		keep[i] = true
+	}
+	return keep
+}
+
+// Apply replays the changes of p on m in order. It stops at the first change that does
+// not fit m, such as deleting a missing key or anchoring to one, and returns an error;
+// the changes before it remain applied.
+func Apply[K comparable, V any](m *Map[K, V], p Patch[K, V]) error {
+	m.lazyInit()
+	for _, op := range p {
+		at := &m.kl.root
+		if op.After != nil && (op.Kind == OpInsert || op.Kind == OpMove) {
+			e, ok := m.kv[*op.After]
+			if !ok {
+				return fmt.Errorf("omap: cannot %v key %v after missing key %v", op.Kind, op.Key, *op.After)
+			}
+			at = e
+		}
+
+		e, exists := m.kv[op.Key]
+		switch {
+		case op.Kind == OpInsert && exists:
+			return fmt.Errorf("omap: cannot insert existing key %v", op.Key)
+		case op.Kind == OpInsert:
+			m.kv[op.Key] = m.kl.insert(&Element[K, V]{key: op.Key, val: op.Value}, at)
+		case !exists:
+			return fmt.Errorf("omap: cannot %v missing key %v", op.Kind, op.Key)
+		case op.Kind == OpDelete:
+			m.kl.delete(e)
+			delete(m.kv, op.Key)
+		case op.Kind == OpUpdate:
+			e.val = op.Value
+		case op.Kind == OpMove:
+			if e == at {
+				return fmt.Errorf("omap: cannot move key %v after itself", op.Key)
+			}
+			m.kl.move(e, at)
+			e.val = op.Value
+		default:
+			return fmt.Errorf("omap: unknown op kind %v", op.Kind)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package omap
+
+import (
+	"math/rand/v2"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a := mapOf[int]("a", 1, "b", 2, "c", 3, "d", 4, "e", 5)
+	b := mapOf[int]("a", 1, "x", 9, "c", 30, "d", 4, "b", 2, "e", 5)
+
+	p := Diff(a, b)
+	want := "" +
+		"+ x: 9 (after a)\n" +
+		"- c: 3\n" +
+		"+ c: 30\n" +
+		"- b: 2\n" +
+		"+ b: 2 (moved, after d)\n"
+	if got := p.String(); got != want {
+		t.Errorf("Diff().String() =\n%s\nwant\n%s", got, want)
+	}
+
+	if err := Apply(a, p); err != nil {
+		t.Fatalf("Apply() = %v", err)
+	}
+	if !a.Equal(b) {
+		t.Errorf("Apply() = %v, want %v", a.Keys(), b.Keys())
+	}
+	if p := Diff(a, b); len(p) != 0 {
+		t.Errorf("Diff() of equal maps = %v", p)
+	}
+}
+
+func TestDiff_Kinds(t *testing.T) {
+	a := mapOf[int]("a", 1, "b", 2, "c", 3)
+	b := mapOf[int]("c", 3, "a", 10, "d", 4)
+
+	kinds := map[OpKind]int{}
+	for _, op := range Diff(a, b) {
+		kinds[op.Kind]++
+	}
+	// c stays in place, a moves behind it with a new value
+	if kinds[OpDelete] != 1 || kinds[OpInsert] != 1 || kinds[OpMove] != 1 || kinds[OpUpdate] != 0 {
+		t.Errorf("Diff() kinds = %v", kinds)
+	}
+
+	p := Diff(nil, b)
+	if len(p) != 3 || p[0].Kind != OpInsert || p[0].After != nil || *p[1].After != "c" {
+		t.Errorf("Diff(nil, b) = %v", p)
+	}
+	if p := Diff(a, nil); len(p) != 3 || p[2].Kind != OpDelete {
+		t.Errorf("Diff(a, nil) = %v", p)
+	}
+
+	// values that panic on == are compared deeply
+	type box struct{ X any }
+	x := mapOf[box]("a", box{[]int{1}}, "b", box{[]int{2}})
+	y := mapOf[box]("a", box{[]int{1}}, "b", box{[]int{3}})
+	if p := Diff(x, y); len(p) != 1 || p[0].Kind != OpUpdate || p[0].Key != "b" {
+		t.Errorf("Diff() of boxed slices = %v", p)
+	}
+}
+
+func TestDiff_Random(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	for range 200 {
+		a, b := New[int, int](), New[int, int]()
+		for _, k := range r.Perm(20)[:r.IntN(20)] {
+			a.Set(k, r.IntN(3))
+		}
+		for _, k := range r.Perm(20)[:r.IntN(20)] {
+			b.Set(k, r.IntN(3))
+		}
+
+		p := Diff(a, b)
+		moves := 0
+		for _, op := range p {
+			if op.Kind == OpMove {
+				moves++
+			}
+		}
+		common := Intersect(a, b, nil).Keys()
+		var seq []int
+		for _, k := range b.Keys() {
+			if a.Has(k) {
+				seq = append(seq, a.IndexOf(k))
+			}
+		}
+		if want := len(common) - lisLength(seq); moves != want {
+			t.Fatalf("Diff() has %d moves, want %d", moves, want)
+		}
+
+		c := a.Clone()
+		if err := Apply(c, p); err != nil {
+			t.Fatalf("Apply() = %v", err)
+		}
+		if !c.Equal(b) {
+			t.Fatalf("Apply(a, Diff(a, b)) = %v, want %v", c.Keys(), b.Keys())
+		}
+	}
+}
+
+// lisLength returns the length of a longest increasing subsequence in O(n^2).
+func lisLength(s []int) int {
+	best := 0
+	l := make([]int, len(s))
+	for i := range s {
+		l[i] = 1
+		for j := range i {
+			if s[j] < s[i] {
+				l[i] = max(l[i], l[j]+1)
+			}
+		}
+		best = max(best, l[i])
+	}
+	return best
+}
+
+func TestApply_Errors(t *testing.T) {
+	missing := "zz"
+	tests := []struct {
+		name string
+		op   Op[string, int]
+	}{
+		{"insert existing", Op[string, int]{Kind: OpInsert, Key: "a"}},
+		{"insert after missing", Op[string, int]{Kind: OpInsert, Key: "x", After: &missing}},
+		{"delete missing", Op[string, int]{Kind: OpDelete, Key: "x"}},
+		{"update missing", Op[string, int]{Kind: OpUpdate, Key: "x"}},
+		{"move after missing", Op[string, int]{Kind: OpMove, Key: "a", After: &missing}},
+		{"unknown kind", Op[string, int]{Kind: OpKind(9), Key: "a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newABC()
+			if err := Apply(m, Patch[string, int]{tt.op}); err == nil {
+				t.Error("Apply() = nil, want an error")
+			}
+			if !m.Equal(newABC()) {
+				t.Errorf("Apply() changed the map to %v", m.Keys())
+			}
+		})
+	}
+}
@@ -10,9 +10,10 @@ It leverages Go iterators (iter.Seq2) and supports generic key-value types.
 
   - Ordered: Maintains insertion order of elements
   - Generic: Supports any comparable key and any value type
-  - Efficient: O(1) time complexity for Set, Get, Delete, and Has operations
+  - Efficient: O(1) time complexity for Get and Has, and for Set and Delete until
+    positional access builds the order-statistics index; O(log n) afterwards
   - Iteration: Supports range iterator via All() method
   - Serialization: Supports JSON/YAML marshaling/unmarshaling, preserving order
-  - Sorting: Provides in-place sorting capabilities
+  - Sorting: Provides in-place sorting capabilities, and an always-sorted SortedMap
 */
 package omap
@@ -0,0 +1,137 @@
+package omap
+
+import (
+	"cmp"
+	"hash/maphash"
+	"reflect"
+)
+
+// valueEqual reports whether a and b are equal. Values of comparable types are
+// compared with ==, others with reflect.DeepEqual. Interface values are checked
+// based on their dynamic value, see isComparable.
+func valueEqual[V any](a, b V) bool {
+	x, y := any(a), any(b)
+	if isComparable(x) && isComparable(y) {
+		return x == y
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+// isComparable reports whether x supports ==. A comparable type may still hold
+// values that panic on ==, such as a struct with an interface field holding a slice,
+// so those are compared once to find out.
+func isComparable(x any) (ok bool) {
+	if x == nil {
+		return true
+	}
+	if !reflect.TypeOf(x).Comparable() {
+		return false
+	}
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	y := x
+	return x == y
+}
+
+func (m *Map[K, V]) len() int {
+	if m == nil {
+		return 0
+	}
+	return len(m.kv)
+}
+
+// Equal reports whether both maps contain the same key-value pairs in the same order.
+// Values are compared with == if their type is comparable, or with reflect.DeepEqual otherwise.
+// A nil map is equal to an empty map.
+func (m *Map[K, V]) Equal(other *Map[K, V]) bool {
+	return m.EqualFunc(other, valueEqual[V])
+}
+
+// EqualFunc reports whether both maps contain the same keys in the same order,
+// and their values are equal according to eq.
+func (m *Map[K, V]) EqualFunc(other *Map[K, V], eq func(V, V) bool) bool {
+	if m == other {
+		return true
+	}
+	if m.len() != other.len() {
+		return false
+	}
+	if m.len() == 0 {
+		return true
+	}
+
+	e2 := other.kl.root.next
+	for e1 := m.kl.root.next; e1 != &m.kl.root; e1 = e1.next {
+		if e1.key != e2.key || !eq(e1.val, e2.val) {
+			return false
+		}
+		e2 = e2.next
+	}
+	return true
+}
+
+// EqualUnordered reports whether both maps contain the same key-value pairs, regardless of their order.
+// Values are compared like in Equal.
+func (m *Map[K, V]) EqualUnordered(other *Map[K, V]) bool {
+	if m == other {
+		return true
+	}
+	if m.len() != other.len() {
+		return false
+	}
+	if m.len() == 0 {
+		return true
+	}
+
+	for k, e1 := range m.kv {
+		e2, ok := other.kv[k]
+		if !ok || !valueEqual(e1.val, e2.val) {
+			return false
+		}
+	}
+	return true
+}
+
+// Compare compares two maps lexicographically by their entries in order.
+// Entries are compared by key first and then by value. If one map is a prefix
+// of the other, the shorter map is less. It returns -1, 0 or +1.
+func Compare[K, V cmp.Ordered](a, b *Map[K, V]) int {
+	n := min(a.len(), b.len())
+	if n > 0 {
+		e1, e2 := a.kl.root.next, b.kl.root.next
+		for range n {
+			if c := cmp.Compare(e1.key, e2.key); c != 0 {
+				return c
+			}
+			if c := cmp.Compare(e1.val, e2.val); c != 0 {
+				return c
+			}
+			e1, e2 = e1.next, e2.next
+		}
+	}
+	return cmp.Compare(a.len(), b.len())
+}
+
+// Hash returns a hash of the map's entries in order, consistent with Equal:
+// equal maps have equal hashes for the same seed.
+// Values that are not comparable with == do not contribute to the hash.
+func (m *Map[K, V]) Hash(seed maphash.Seed) uint64 {
+	var h maphash.Hash
+	h.SetSeed(seed)
+	if m.len() == 0 {
+		return h.Sum64()
+	}
+
+	for e := m.kl.root.next; e != &m.kl.root; e = e.next {
+		maphash.WriteComparable(&h, e.key)
+		if v := any(e.val); isComparable(v) {
+			maphash.WriteComparable(&h, v)
+		} else {
+			h.WriteByte(0)
+		}
+	}
+	return h.Sum64()
+}
@@ -0,0 +1,147 @@
+package omap
+
+import (
+	"hash/maphash"
+	"strings"
+	"testing"
+)
+
+func mapOf[V any](pairs ...any) *Map[string, V] {
+	m := New[string, V]()
+	for i := 0; i < len(pairs); i += 2 {
+		m.Set(pairs[i].(string), pairs[i+1].(V))
+	}
+	return m
+}
+
+func TestMap_Equal(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *Map[string, int]
+		want bool
+	}{
+		{"Same", mapOf[int]("a", 1, "b", 2), mapOf[int]("a", 1, "b", 2), true},
+		{"Different order", mapOf[int]("a", 1, "b", 2), mapOf[int]("b", 2, "a", 1), false},
+		{"Different value", mapOf[int]("a", 1, "b", 2), mapOf[int]("a", 1, "b", 3), false},
+		{"Different length", mapOf[int]("a", 1), mapOf[int]("a", 1, "b", 2), false},
+		{"Empty", New[string, int](), &Map[string, int]{}, true},
+		{"Nil and empty", nil, New[string, int](), true},
+		{"Nil and non-empty", nil, mapOf[int]("a", 1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b); got != tt.want {
+				t.Errorf("a.Equal(b) = %v, want %v", got, tt.want)
+			}
+			if got := tt.b.Equal(tt.a); got != tt.want {
+				t.Errorf("b.Equal(a) = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Incomparable values", func(t *testing.T) {
+		a := mapOf[any]("a", []any{1, "x"}, "b", 2)
+		b := mapOf[any]("a", []any{1, "x"}, "b", 2)
+		if !a.Equal(b) {
+			t.Error("maps with equal slice values should be equal")
+		}
+		b.Set("a", []any{1, "y"})
+		if a.Equal(b) {
+			t.Error("maps with different slice values should not be equal")
+		}
+	})
+
+	t.Run("Incomparable dynamic values", func(t *testing.T) {
+		type box struct{ X any }
+		a := mapOf[box]("a", box{[]int{1}})
+		b := mapOf[box]("a", box{[]int{1}})
+		if !a.Equal(b) {
+			t.Error("maps with equal boxed slices should be equal")
+		}
+		b.Set("a", box{[]int{2}})
+		if a.Equal(b) {
+			t.Error("maps with different boxed slices should not be equal")
+		}
+	})
+}
+
+func TestMap_EqualFunc(t *testing.T) {
+	a := mapOf[string]("a", "Foo", "b", "BAR")
+	b := mapOf[string]("a", "foo", "b", "bar")
+
+	if a.Equal(b) {
+		t.Error("Equal() should be case sensitive")
+	}
+	if !a.EqualFunc(b, strings.EqualFold) {
+		t.Error("EqualFunc(EqualFold) = false, want true")
+	}
+}
+
+func TestMap_EqualUnordered(t *testing.T) {
+	a := mapOf[int]("a", 1, "b", 2)
+
+	if !a.EqualUnordered(mapOf[int]("b", 2, "a", 1)) {
+		t.Error("EqualUnordered() with different order = false, want true")
+	}
+	if a.EqualUnordered(mapOf[int]("b", 2, "c", 1)) {
+		t.Error("EqualUnordered() with different keys = true, want false")
+	}
+	if a.EqualUnordered(mapOf[int]("b", 2, "a", 3)) {
+		t.Error("EqualUnordered() with different values = true, want false")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b *Map[string, int]
+		want int
+	}{
+		{"Equal", mapOf[int]("a", 1, "b", 2), mapOf[int]("a", 1, "b", 2), 0},
+		{"Key less", mapOf[int]("a", 1, "b", 2), mapOf[int]("a", 1, "c", 0), -1},
+		{"Value greater", mapOf[int]("a", 2), mapOf[int]("a", 1, "b", 2), 1},
+		{"Prefix", mapOf[int]("a", 1), mapOf[int]("a", 1, "b", 2), -1},
+		{"Nil", nil, New[string, int](), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Compare(tt.a, tt.b); got != tt.want {
+				t.Errorf("Compare(a, b) = %d, want %d", got, tt.want)
+			}
+			if got := Compare(tt.b, tt.a); got != -tt.want {
+				t.Errorf("Compare(b, a) = %d, want %d", got, -tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_Hash(t *testing.T) {
+	seed := maphash.MakeSeed()
+
+	a := mapOf[int]("a", 1, "b", 2)
+	if a.Hash(seed) != mapOf[int]("a", 1, "b", 2).Hash(seed) {
+		t.Error("equal maps should have equal hashes")
+	}
+	if a.Hash(seed) == mapOf[int]("b", 2, "a", 1).Hash(seed) {
+		t.Error("maps in different order should have different hashes")
+	}
+	if a.Hash(seed) == mapOf[int]("a", 1, "b", 3).Hash(seed) {
+		t.Error("maps with different values should have different hashes")
+	}
+	if New[string, int]().Hash(seed) != (*Map[string, int])(nil).Hash(seed) {
+		t.Error("empty and nil maps should have equal hashes")
+	}
+
+	// Incomparable values are skipped instead of panicking
+	x := mapOf[any]("a", []int{1}, "b", 2)
+	y := mapOf[any]("a", []int{1}, "b", 2)
+	if x.Hash(seed) != y.Hash(seed) {
+		t.Error("equal maps with slice values should have equal hashes")
+	}
+	type box struct{ X any }
+	if mapOf[box]("a", box{[]int{1}}).Hash(seed) != mapOf[box]("a", box{[]int{1}}).Hash(seed) {
+		t.Error("equal maps with boxed slice values should have equal hashes")
+	}
+}
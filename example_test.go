@@ -3,6 +3,7 @@ package omap
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 
 	"go.yaml.in/yaml/v3"
 )
@@ -244,3 +245,474 @@ func ExampleSortFunc() {
 	fmt.Println(m.Keys())
 	// Output: [banana cherry apple]
 }
+
+func ExampleMap_Front() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+
+	for e := m.Front(); e != nil; e = e.Next() {
+		fmt.Printf("%s: %d\n", e.Key(), e.Value())
+	}
+	// Output:
+	// a: 1
+	// b: 2
+	// c: 3
+}
+
+func ExampleMap_GetElement() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+
+	e := m.GetElement("b")
+	fmt.Println(e.Prev().Key(), e.Next().Key())
+
+	e.SetValue(20)
+	fmt.Println(m.Get("b"))
+	// Output:
+	// a c
+	// 20
+}
+
+func ExampleMap_InsertBefore() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("c", 3)
+
+	m.InsertBefore("c", "b", 2)
+	fmt.Println(m.Keys())
+	// Output: [a b c]
+}
+
+func ExampleMap_MoveToFront() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+
+	m.MoveToFront("c")
+	fmt.Println(m.Keys())
+	// Output: [c a b]
+}
+
+func ExampleMap_Rename() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+
+	m.Rename("b", "x")
+	fmt.Println(m.Keys())
+	fmt.Println(m.Values())
+	// Output:
+	// [a x c]
+	// [1 2 3]
+}
+
+func ExampleMap_At() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+
+	k, v := m.At(1)
+	fmt.Println(k, v)
+	fmt.Println(m.IndexOf("c"))
+	// Output:
+	// b 2
+	// 2
+}
+
+func ExampleMap_Slice() {
+	m := New[int, string]()
+	for i, s := range []string{"zero", "one", "two", "three", "four"} {
+		m.Set(i, s)
+	}
+
+	for k, v := range m.Slice(1, 3) {
+		fmt.Println(k, v)
+	}
+	// Output:
+	// 1 one
+	// 2 two
+}
+
+func ExampleMap_Backward() {
+	m := New[string, int]()
+	m.Set("one", 1)
+	m.Set("two", 2)
+	m.Set("three", 3)
+
+	for k, v := range m.Backward() {
+		fmt.Printf("%s: %d\n", k, v)
+	}
+	// Output:
+	// three: 3
+	// two: 2
+	// one: 1
+}
+
+func ExampleMap_Enumerate() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+
+	for i, e := range m.Enumerate() {
+		fmt.Println(i, e.Key, e.Value)
+	}
+	// Output:
+	// 0 a 1
+	// 1 b 2
+}
+
+func ExampleMap_Between() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+	m.Set("d", 4)
+
+	for k := range m.Between("b", "c") {
+		fmt.Println(k)
+	}
+	// Output:
+	// b
+	// c
+}
+
+func ExampleMap_Compute() {
+	m := New[string, int]()
+	for _, w := range []string{"apple", "banana", "apple"} {
+		m.Compute(w, func(old int, exists bool) (int, bool) {
+			return old + 1, true
+		})
+	}
+
+	for k, v := range m.All() {
+		fmt.Println(k, v)
+	}
+	// Output:
+	// apple 2
+	// banana 1
+}
+
+func ExampleMap_GetOrSet() {
+	m := New[string, int]()
+	m.Set("a", 1)
+
+	fmt.Println(m.GetOrSet("a", 10))
+	fmt.Println(m.GetOrSet("b", 2))
+	// Output:
+	// 1 true
+	// 2 false
+}
+
+func ExampleMap_DeleteFunc() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+	m.Set("d", 4)
+
+	n := m.DeleteFunc(func(k string, v int) bool {
+		return v%2 == 0
+	})
+	fmt.Println(n, m.Keys())
+	// Output: 2 [a c]
+}
+
+func ExampleMap_Truncate() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+
+	n := m.Truncate(1)
+	fmt.Println(n, m.Keys())
+	// Output: 2 [a]
+}
+
+func ExampleMap_PopFirst() {
+	queue := New[string, int]()
+	queue.Set("job1", 1)
+	queue.Set("job2", 2)
+	queue.Set("job1", 3) // de-duplicated, keeps its place
+
+	for {
+		k, v, ok := queue.PopFirst()
+		if !ok {
+			break
+		}
+		fmt.Println(k, v)
+	}
+	// Output:
+	// job1 3
+	// job2 2
+}
+
+func ExampleMap_MergeFunc() {
+	base := New[string, int]()
+	base.Set("host", 1)
+	base.Set("port", 2)
+	base.Set("debug", 3)
+
+	override := New[string, int]()
+	override.Set("debug", 30)
+	override.Set("port", 20)
+
+	base.MergeFunc(nil, MergeOptions{Position: PositionIncoming}, override)
+	fmt.Println(base.Keys())
+	fmt.Println(base.Values())
+	// Output:
+	// [host debug port]
+	// [1 30 20]
+}
+
+func ExampleMap_Clone() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+
+	c := m.Clone()
+	c.Set("c", 3)
+
+	fmt.Println(m.Keys())
+	fmt.Println(c.Keys())
+	// Output:
+	// [a b]
+	// [a b c]
+}
+
+func ExampleMap_Equal() {
+	a := New[string, int]()
+	a.Set("x", 1)
+	a.Set("y", 2)
+
+	b := New[string, int]()
+	b.Set("y", 2)
+	b.Set("x", 1)
+
+	fmt.Println(a.Equal(b))
+	fmt.Println(a.EqualUnordered(b))
+	// Output:
+	// false
+	// true
+}
+
+func ExampleSortByValue() {
+	m := New[string, int]()
+	m.Set("cpu", 70)
+	m.Set("mem", 30)
+	m.Set("disk", 50)
+
+	SortByValue(m)
+	fmt.Println(m.Keys())
+	// Output: [mem disk cpu]
+}
+
+func ExampleSortedMap() {
+	s := NewSorted[int, string]()
+	s.Set(30, "c")
+	s.Set(10, "a")
+	s.Set(20, "b")
+
+	fmt.Println(s.Keys())
+
+	k, v, _ := s.Floor(25)
+	fmt.Println(k, v)
+
+	for k, v := range s.Range(15, 35) {
+		fmt.Println(k, v)
+	}
+	// Output:
+	// [10 20 30]
+	// 20 b
+	// 20 b
+	// 30 c
+}
+
+func ExampleNaturalCompare() {
+	m := New[string, int]()
+	m.Set("item10", 10)
+	m.Set("item2", 2)
+	m.Set("item1", 1)
+
+	SortFunc(m, NaturalCompare)
+	fmt.Println(m.Keys())
+	// Output: [item1 item2 item10]
+}
+
+func ExampleSemverCompare() {
+	m := New[string, string]()
+	m.Set("v1.10.0", "latest")
+	m.Set("v1.9.0", "old")
+	m.Set("v1.10.0-rc.1", "candidate")
+
+	SortFunc(m, SemverCompare)
+	fmt.Println(m.Keys())
+	// Output: [v1.9.0 v1.10.0-rc.1 v1.10.0]
+}
+
+func ExampleMap_SplitAt() {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+	m.Set("d", 4)
+
+	head, tail := m.SplitAt("c")
+	fmt.Println(head.Keys(), tail.Keys())
+
+	head.Concat(tail)
+	fmt.Println(head.Keys(), tail.Len())
+	// Output:
+	// [a b] [c d]
+	// [a b c d] 0
+}
+
+func ExampleUnion() {
+	a := New[string, int]()
+	a.Set("x", 1)
+	a.Set("y", 2)
+
+	b := New[string, int]()
+	b.Set("z", 30)
+	b.Set("y", 20)
+
+	sum := func(key string, a, b int) int { return a + b }
+	fmt.Println(Union(a, b, sum).Keys(), Union(a, b, sum).Values())
+	fmt.Println(Intersect(a, b, nil).Values())
+	fmt.Println(Difference(a, b).Keys())
+	fmt.Println(SymmetricDifference(a, b).Keys())
+	// Output:
+	// [x y z] [1 22 30]
+	// [20]
+	// [x]
+	// [x z]
+}
+
+func ExampleDiff() {
+	a := New[string, int]()
+	a.Set("host", 1)
+	a.Set("port", 2)
+	a.Set("user", 3)
+
+	b := New[string, int]()
+	b.Set("user", 3)
+	b.Set("host", 10)
+	b.Set("tls", 4)
+
+	p := Diff(a, b)
+	fmt.Print(p)
+
+	_ = Apply(a, p)
+	fmt.Println(a.Equal(b))
+	// Output:
+	// - port: 2
+	// - host: 1
+	// + host: 10 (moved, after user)
+	// + tls: 4 (after host)
+	// true
+}
+
+func ExampleMerge3() {
+	base := New[string, int]()
+	base.Set("a", 1)
+	base.Set("b", 2)
+	base.Set("c", 3)
+
+	ours := base.Clone()
+	ours.Set("a", 10) // change a
+	ours.Set("d", 4)  // add d
+
+	theirs := base.Clone()
+	theirs.MoveToFront("c") // reorder
+	theirs.Set("a", 11)     // change a as well
+
+	m, conflicts := Merge3(base, ours, theirs, func(c Conflict[string, int]) (int, bool) {
+		return max(c.Ours, c.Theirs), true
+	})
+	fmt.Println(m.Keys(), m.Values())
+	for _, c := range conflicts {
+		fmt.Println(c.Kind, c.Key, c.Ours, c.Theirs)
+	}
+	// Output:
+	// [c a b d] [3 11 2 4]
+	// modify/modify a 10 11
+}
+
+func ExampleSyncMap() {
+	s := NewSync[string, int]()
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			s.Compute("hits", func(old int, exists bool) (int, bool) {
+				return old + 1, true
+			})
+		})
+	}
+	wg.Wait()
+
+	for k, v := range s.All() { // iterates over a snapshot without holding the lock
+		fmt.Println(k, v)
+	}
+	// Output: hits 10
+}
+
+func ExampleCOWMap() {
+	routes := NewCOW[string, string]()
+
+	// writers batch their changes into a single new version
+	routes.Update(func(m *Map[string, string]) {
+		m.Set("/api", "backend")
+		m.Set("/", "frontend")
+	})
+
+	// readers never block
+	fmt.Println(routes.Get("/api"), routes.Keys())
+	// Output: backend [/api /]
+}
+
+func ExampleShardedMap() {
+	s := NewSharded[string, int](16)
+	s.Set("a", 1)
+	s.Set("b", 2)
+	s.Set("c", 3)
+
+	// the keys live in different shards, but keep their insertion order
+	fmt.Println(s.Keys(), s.Len())
+	// Output: [a b c] 3
+}
+
+func ExampleImmutableMap() {
+	v1 := NewImmutable[string, int]().Set("a", 1).Set("b", 2)
+	v2 := v1.Set("a", 10).Delete("b")
+
+	// v1 is unchanged, and shares most of its structure with v2
+	fmt.Println(v1.Keys(), v1.Get("a"))
+	fmt.Println(v2.Keys(), v2.Get("a"))
+	// Output:
+	// [a b] 1
+	// [a] 10
+}
+
+func ExampleNewLRU() {
+	c := NewLRU(2, &CacheOptions[string, int]{
+		OnEvict: func(k string, v int) {
+			fmt.Println("evicted", k)
+		},
+	})
+	c.Set("a", 1)
+	c.Set("b", 2)
+	c.Get("a")    // a is now more recently used than b
+	c.Set("c", 3) // evicts b
+
+	fmt.Println(c.Keys())
+	// Output:
+	// evicted b
+	// [a c]
+}
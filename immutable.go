@@ -0,0 +1,394 @@
+package omap
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+// immutableSeed hashes the keys of all immutable maps. It is shared, so that
+// versions derived from each other agree on the hashes.
+var immutableSeed = maphash.MakeSeed()
+
+// ImmutableMap is a persistent ordered map. Set and Delete leave the map unchanged
+// and return a new version in O(log n), which shares most of its structure with
+// the old one. Keeping many versions, e.g. for undo history, is therefore cheap.
+//
+// Keys are found through a hash array mapped trie, and the insertion order is kept
+// in a tree ordered by the sequence number of each key. Like in Map, updating an
+// existing key keeps its position.
+//
+// The zero value and a nil *ImmutableMap are empty maps. An ImmutableMap is safe for
+// concurrent use. It does not implement OrderedMap, since its Set and Delete return
+// new versions.
+type ImmutableMap[K comparable, V any] struct {
+	keys  *hamtNode[K, V]
+	order *orderNode[K, V]
+	len   int
+	seq   uint64 // the sequence number of the last inserted key
+}
+
+// NewImmutable returns an empty ImmutableMap.
+func NewImmutable[K comparable, V any]() *ImmutableMap[K, V] {
+	return &ImmutableMap[K, V]{}
+}
+
+// ToImmutable returns an ImmutableMap with the entries of the map in the same order.
+func (m *Map[K, V]) ToImmutable() *ImmutableMap[K, V] {
+	im := NewImmutable[K, V]()
+	for k, v := range m.All() {
+		im = im.Set(k, v)
+	}
+	return im
+}
+
+// ToMap returns a Map with the entries of the map in the same order.
+func (im *ImmutableMap[K, V]) ToMap() *Map[K, V] {
+	m := Make[K, V](im.Len())
+	for k, v := range im.All() {
+		m.kv[k] = m.kl.append(k, v)
+	}
+	return m
+}
+
+// Len returns the number of key-value pairs in the map.
+func (im *ImmutableMap[K, V]) Len() int {
+	if im == nil {
+		return 0
+	}
+	return im.len
+}
+
+// Get retrieves the value associated with the given key.
+func (im *ImmutableMap[K, V]) Get(key K) V {
+	v, _ := im.TryGet(key)
+	return v
+}
+
+// TryGet retrieves the value associated with the given key.
+// It returns the value and true if the key exists, otherwise the zero value and false.
+func (im *ImmutableMap[K, V]) TryGet(key K) (V, bool) {
+	if im == nil {
+		var zero V
+		return zero, false
+	}
+	e, ok := im.keys.get(maphash.Comparable(immutableSeed, key), key)
+	return e.val, ok
+}
+
+// Has checks if the map contains the given key.
+func (im *ImmutableMap[K, V]) Has(key K) bool {
+	_, ok := im.TryGet(key)
+	return ok
+}
+
+// Set returns a version of the map in which the key has the given value.
+// A new key is appended to the end; an existing key keeps its position.
+func (im *ImmutableMap[K, V]) Set(key K, value V) *ImmutableMap[K, V] {
+	if im == nil {
+		im = &ImmutableMap[K, V]{}
+	}
+	next := *im
+	e := hamtEntry[K, V]{hash: maphash.Comparable(immutableSeed, key), key: key, val: value}
+	if old, ok := im.keys.get(e.hash, key); ok {
+		e.seq = old.seq
+		next.order = im.order.update(e.seq, value)
+	} else {
+		next.seq++
+		next.len++
+		e.seq = next.seq
+		next.order = im.order.append(&orderNode[K, V]{seq: e.seq, prio: orderPriority(e.seq), key: key, val: value})
+	}
+	next.keys, _ = im.keys.set(e, 0)
+	return &next
+}
+
+// Delete returns a version of the map without the given keys.
+// It returns the map itself if none of the keys exist.
+func (im *ImmutableMap[K, V]) Delete(keys ...K) *ImmutableMap[K, V] {
+	for _, k := range keys {
+		if im == nil {
+			return im
+		}
+		e, ok := im.keys.get(maphash.Comparable(immutableSeed, k), k)
+		if !ok {
+			continue
+		}
+		next := *im
+		next.keys, _ = im.keys.remove(e.hash, k, 0)
+		next.order = im.order.remove(e.seq)
+		next.len--
+		im = &next
+	}
+	return im
+}
+
+// All returns an iterator over the map's entries in insertion order.
+func (im *ImmutableMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if im != nil {
+			im.order.walk(false, yield)
+		}
+	}
+}
+
+// Backward returns an iterator over the map's entries in reverse insertion order.
+func (im *ImmutableMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if im != nil {
+			im.order.walk(true, yield)
+		}
+	}
+}
+
+// Keys returns a slice of all keys in the map, in insertion order.
+func (im *ImmutableMap[K, V]) Keys() []K {
+	keys := make([]K, 0, im.Len())
+	for k := range im.All() {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+// Values returns a slice of all values in the map, in the order of their keys.
+func (im *ImmutableMap[K, V]) Values() []V {
+	values := make([]V, 0, im.Len())
+	for _, v := range im.All() {
+		values = append(values, v)
+	}
+	return values
+}
+
+// hamtBits is the number of hash bits consumed by each level of the trie.
+const hamtBits = 5
+
+// hamtNode is a node of a persistent hash array mapped trie. Its bitmap tells which of
+// the 32 possible slots are present, and slots holds only those, in order.
+type hamtNode[K comparable, V any] struct {
+	bitmap uint32
+	slots  []hamtSlot[K, V]
+}
+
+// hamtSlot holds either a child node or a bucket of entries whose hashes agree on
+// all bits consumed so far. Entries of a bucket with more than one entry have the
+// same full hash.
+type hamtSlot[K comparable, V any] struct {
+	node   *hamtNode[K, V]
+	bucket []hamtEntry[K, V]
+}
+
+type hamtEntry[K comparable, V any] struct {
+	hash uint64
+	seq  uint64
+	key  K
+	val  V
+}
+
+// slot returns the bit of the slot for hash at the level of shift, and its index in slots.
+func (n *hamtNode[K, V]) slot(hash uint64, shift uint) (uint32, int) {
+	bit := uint32(1) << (hash >> shift & (1<<hamtBits - 1))
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+func (n *hamtNode[K, V]) get(hash uint64, key K) (hamtEntry[K, V], bool) {
+	for shift := uint(0); n != nil; shift += hamtBits {
+		bit, i := n.slot(hash, shift)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		s := &n.slots[i]
+		if s.node == nil {
+			for _, e := range s.bucket {
+				if e.hash == hash && e.key == key {
+					return e, true
+				}
+			}
+			break
+		}
+		n = s.node
+	}
+	var zero hamtEntry[K, V]
+	return zero, false
+}
+
+// set returns a copy of n that stores e, and reports whether the key of e is new.
+func (n *hamtNode[K, V]) set(e hamtEntry[K, V], shift uint) (*hamtNode[K, V], bool) {
+	if n == nil {
+		n = &hamtNode[K, V]{}
+	}
+	bit, i := n.slot(e.hash, shift)
+	if n.bitmap&bit == 0 {
+		slots := slices.Insert(slices.Clone(n.slots), i, hamtSlot[K, V]{bucket: []hamtEntry[K, V]{e}})
+		return &hamtNode[K, V]{bitmap: n.bitmap | bit, slots: slots}, true
+	}
+
+	c := &hamtNode[K, V]{bitmap: n.bitmap, slots: slices.Clone(n.slots)}
+	s := &c.slots[i]
+	switch {
+	case s.node != nil:
+		var added bool
+		s.node, added = s.node.set(e, shift+hamtBits)
+		return c, added
+	case s.bucket[0].hash == e.hash:
+		j := slices.IndexFunc(s.bucket, func(old hamtEntry[K, V]) bool {
+			return old.key == e.key
+		})
+		s.bucket = slices.Clone(s.bucket)
+		if j >= 0 {
+			s.bucket[j] = e
+			return c, false
+		}
+		s.bucket = append(s.bucket, e)
+		return c, true
+	default:
+		// push the bucket down a level and add e next to it
+		child := &hamtNode[K, V]{}
+		bit, _ := child.slot(s.bucket[0].hash, shift+hamtBits)
+		child.bitmap = bit
+		child.slots = []hamtSlot[K, V]{{bucket: s.bucket}}
+		*s = hamtSlot[K, V]{}
+		s.node, _ = child.set(e, shift+hamtBits)
+		return c, true
+	}
+}
+
+// remove returns a copy of n without the key and true, or n itself and false if the
+// key does not exist. The copy is nil if it would be empty.
+func (n *hamtNode[K, V]) remove(hash uint64, key K, shift uint) (*hamtNode[K, V], bool) {
+	bit, i := n.slot(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+
+	s := n.slots[i]
+	if s.node != nil {
+		child, ok := s.node.remove(hash, key, shift+hamtBits)
+		switch {
+		case !ok:
+			return n, false
+		case child == nil:
+			return n.without(bit, i), true
+		case len(child.slots) == 1 && child.slots[0].node == nil:
+			// a lone bucket moves up, since its hashes agree on the bits of this level
+			return n.with(i, child.slots[0]), true
+		}
+		return n.with(i, hamtSlot[K, V]{node: child}), true
+	}
+
+	j := slices.IndexFunc(s.bucket, func(e hamtEntry[K, V]) bool {
+		return e.hash == hash && e.key == key
+	})
+	switch {
+	case j < 0:
+		return n, false
+	case len(s.bucket) == 1:
+		return n.without(bit, i), true
+	}
+	return n.with(i, hamtSlot[K, V]{bucket: slices.Delete(slices.Clone(s.bucket), j, j+1)}), true
+}
+
+// with returns a copy of n whose slot i is s.
+func (n *hamtNode[K, V]) with(i int, s hamtSlot[K, V]) *hamtNode[K, V] {
+	c := &hamtNode[K, V]{bitmap: n.bitmap, slots: slices.Clone(n.slots)}
+	c.slots[i] = s
+	return c
+}
+
+// without returns a copy of n without slot i, or nil if that was its only slot.
+func (n *hamtNode[K, V]) without(bit uint32, i int) *hamtNode[K, V] {
+	if n.bitmap == bit {
+		return nil
+	}
+	return &hamtNode[K, V]{bitmap: n.bitmap &^ bit, slots: slices.Delete(slices.Clone(n.slots), i, i+1)}
+}
+
+// orderNode is a node of a persistent treap ordered by sequence number, which keeps
+// the insertion order of an ImmutableMap. Nodes are never modified once they are
+// part of a tree; changes copy the nodes on the path from the root.
+type orderNode[K comparable, V any] struct {
+	left, right *orderNode[K, V]
+	seq, prio   uint64
+	key         K
+	val         V
+}
+
+// orderPriority derives the treap priority from the sequence number, so that
+// the shape of the tree does not depend on randomness. It is the splitmix64 finalizer.
+func orderPriority(seq uint64) uint64 {
+	seq = (seq ^ seq>>30) * 0xbf58476d1ce4e5b9
+	seq = (seq ^ seq>>27) * 0x94d049bb133111eb
+	return seq ^ seq>>31
+}
+
+// append returns a tree with x added to n, where x is a new node whose sequence number
+// is larger than all others.
+func (n *orderNode[K, V]) append(x *orderNode[K, V]) *orderNode[K, V] {
+	if n == nil {
+		return x
+	}
+	if x.prio > n.prio {
+		x.left = n
+		return x
+	}
+	c := *n
+	c.right = n.right.append(x)
+	return &c
+}
+
+// update returns a tree in which the node with the sequence number has the value.
+func (n *orderNode[K, V]) update(seq uint64, val V) *orderNode[K, V] {
+	c := *n
+	switch {
+	case seq < n.seq:
+		c.left = n.left.update(seq, val)
+	case seq > n.seq:
+		c.right = n.right.update(seq, val)
+	default:
+		c.val = val
+	}
+	return &c
+}
+
+// remove returns a tree without the node with the sequence number.
+func (n *orderNode[K, V]) remove(seq uint64) *orderNode[K, V] {
+	c := *n
+	switch {
+	case seq < n.seq:
+		c.left = n.left.remove(seq)
+	case seq > n.seq:
+		c.right = n.right.remove(seq)
+	default:
+		return joinOrder(n.left, n.right)
+	}
+	return &c
+}
+
+// joinOrder joins two trees, where all nodes of a precede those of b.
+func joinOrder[K comparable, V any](a, b *orderNode[K, V]) *orderNode[K, V] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.prio > b.prio:
+		c := *a
+		c.right = joinOrder(a.right, b)
+		return &c
+	}
+	c := *b
+	c.left = joinOrder(a, b.left)
+	return &c
+}
+
+// walk yields the entries of the tree in order, and reports whether yield always returned true.
+func (n *orderNode[K, V]) walk(backward bool, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	first, last := n.left, n.right
+	if backward {
+		first, last = last, first
+	}
+	return first.walk(backward, yield) && yield(n.key, n.val) && last.walk(backward, yield)
+}
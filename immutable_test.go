@@ -0,0 +1,228 @@
+package omap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestImmutableMap(t *testing.T) {
+	var empty *ImmutableMap[string, int] // a nil map is empty
+	if empty.Len() != 0 || empty.Has("a") || len(empty.Keys()) != 0 || empty.Delete("a") != nil {
+		t.Fatal("nil map is not empty")
+	}
+
+	v1 := empty.Set("a", 1).Set("b", 2).Set("c", 3)
+	v2 := v1.Set("a", 10) // keeps its position
+	v3 := v2.Delete("b", "missing")
+	v4 := v3.Set("b", 20) // moves to the end
+
+	tests := []struct {
+		m          *ImmutableMap[string, int]
+		keys       []string
+		values     []int
+		backward   []string
+		len        int
+		hasB, hasA bool
+	}{
+		{v1, []string{"a", "b", "c"}, []int{1, 2, 3}, []string{"c", "b", "a"}, 3, true, true},
+		{v2, []string{"a", "b", "c"}, []int{10, 2, 3}, []string{"c", "b", "a"}, 3, true, true},
+		{v3, []string{"a", "c"}, []int{10, 3}, []string{"c", "a"}, 2, false, true},
+		{v4, []string{"a", "c", "b"}, []int{10, 3, 20}, []string{"b", "c", "a"}, 3, true, true},
+	}
+	for i, tt := range tests {
+		if got := tt.m.Keys(); !slices.Equal(got, tt.keys) {
+			t.Errorf("v%d.Keys() = %v, want %v", i+1, got, tt.keys)
+		}
+		if got := tt.m.Values(); !slices.Equal(got, tt.values) {
+			t.Errorf("v%d.Values() = %v, want %v", i+1, got, tt.values)
+		}
+		var backward []string
+		for k := range tt.m.Backward() {
+			backward = append(backward, k)
+		}
+		if !slices.Equal(backward, tt.backward) {
+			t.Errorf("v%d.Backward() = %v, want %v", i+1, backward, tt.backward)
+		}
+		if tt.m.Len() != tt.len || tt.m.Has("b") != tt.hasB || tt.m.Has("a") != tt.hasA {
+			t.Errorf("v%d.Len() = %d, Has(b) = %v", i+1, tt.m.Len(), tt.m.Has("b"))
+		}
+	}
+
+	if v3.Delete("missing") != v3 {
+		t.Error("Delete() of a missing key returned a new version")
+	}
+	if v, ok := v4.TryGet("b"); !ok || v != 20 || v4.Get("missing") != 0 {
+		t.Errorf("TryGet(b) = %d, %v", v, ok)
+	}
+
+	var keys []string
+	for k := range v4.All() {
+		keys = append(keys, k)
+		break
+	}
+	if !slices.Equal(keys, []string{"a"}) {
+		t.Errorf("All() with break = %v", keys)
+	}
+}
+
+func TestImmutableMap_Conversion(t *testing.T) {
+	m := New[int, string]()
+	m.Set(3, "c")
+	m.Set(1, "a")
+	m.Set(2, "b")
+
+	im := m.ToImmutable()
+	m.Set(4, "d") // does not affect im
+	if !slices.Equal(im.Keys(), []int{3, 1, 2}) {
+		t.Errorf("ToImmutable() = %v", im.Keys())
+	}
+
+	back := im.Set(5, "e").ToMap()
+	back.Set(6, "f") // does not affect im
+	if !slices.Equal(back.Keys(), []int{3, 1, 2, 5, 6}) || back.Get(1) != "a" {
+		t.Errorf("ToMap() = %v", back.Keys())
+	}
+	if im.Len() != 3 {
+		t.Errorf("Len() = %d after converting, want 3", im.Len())
+	}
+}
+
+func TestImmutableMap_Random(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	type version struct {
+		im    *ImmutableMap[int, int]
+		model *Map[int, int]
+	}
+	versions := []version{{NewImmutable[int, int](), New[int, int]()}}
+	for range 3000 {
+		// derive the next version from a random earlier one
+		v := versions[r.IntN(len(versions))]
+		im, model := v.im, v.model.Clone()
+		for range 1 + r.IntN(5) {
+			k := r.IntN(200)
+			if r.IntN(3) == 0 {
+				im = im.Delete(k)
+				model.Delete(k)
+			} else {
+				im = im.Set(k, k*r.IntN(10))
+				model.Set(k, im.Get(k))
+			}
+		}
+		versions = append(versions, version{im, model})
+	}
+
+	// every version must still match its model
+	for i, v := range versions {
+		if !slices.Equal(v.im.Keys(), v.model.Keys()) || !slices.Equal(v.im.Values(), v.model.Values()) {
+			t.Fatalf("version %d = %v, want %v", i, v.im.Keys(), v.model.Keys())
+		}
+		if v.im.Len() != v.model.Len() {
+			t.Fatalf("version %d: Len() = %d, want %d", i, v.im.Len(), v.model.Len())
+		}
+		for k := range 200 {
+			got, ok := v.im.TryGet(k)
+			want, wantOK := v.model.TryGet(k)
+			if got != want || ok != wantOK {
+				t.Fatalf("version %d: TryGet(%d) = %d, %v, want %d, %v", i, k, got, ok, want, wantOK)
+			}
+		}
+	}
+}
+
+func TestImmutableMap_Sharing(t *testing.T) {
+	im := NewImmutable[int, int]()
+	for i := range 10000 {
+		im = im.Set(i, i)
+	}
+	next := im.Set(5000, 0)
+
+	// only the paths to the changed entry are copied
+	if shared := countShared(im.keys, next.keys); shared < 300 {
+		t.Errorf("new version shares %d trie nodes, want most of them", shared)
+	}
+	if depth := orderDepth(im.order); depth > 60 {
+		t.Errorf("order tree has depth %d for %d keys", depth, im.Len())
+	}
+}
+
+// countShared returns the number of trie nodes that a and b have in common.
+func countShared[K comparable, V any](a, b *hamtNode[K, V]) int {
+	if a == b {
+		return countNodes(a)
+	}
+	n := 0
+	for i, s := range a.slots {
+		if s.node != nil && i < len(b.slots) {
+			n += countShared(s.node, b.slots[i].node)
+		}
+	}
+	return n
+}
+
+func countNodes[K comparable, V any](n *hamtNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	count := 1
+	for _, s := range n.slots {
+		count += countNodes(s.node)
+	}
+	return count
+}
+
+func orderDepth[K comparable, V any](n *orderNode[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	return 1 + max(orderDepth(n.left), orderDepth(n.right))
+}
+
+func TestHAMT_Collisions(t *testing.T) {
+	// the trie is used directly, to control the hashes
+	entry := func(hash uint64, key string) hamtEntry[string, int] {
+		return hamtEntry[string, int]{hash: hash, key: key, val: len(key)}
+	}
+	const same, near = 0xdead_beef, 0xdead_beef | 1<<62 // near differs only in the last level
+
+	var n *hamtNode[string, int]
+	var added bool
+	for _, e := range []hamtEntry[string, int]{entry(same, "a"), entry(same, "bb"), entry(near, "ccc"), entry(same, "bb")} {
+		n, added = n.set(e, 0)
+	}
+	if added {
+		t.Error("set() of an existing key reported it as new")
+	}
+	for _, e := range []hamtEntry[string, int]{entry(same, "a"), entry(same, "bb"), entry(near, "ccc")} {
+		if got, ok := n.get(e.hash, e.key); !ok || got.val != e.val {
+			t.Errorf("get(%s) = %v, %v", e.key, got, ok)
+		}
+	}
+	if _, ok := n.get(same, "ccc"); ok {
+		t.Error("get() found a key under the wrong hash")
+	}
+
+	n, _ = n.remove(near, "ccc", 0)
+	if len(n.slots) != 1 || n.slots[0].node != nil || len(n.slots[0].bucket) != 2 {
+		t.Errorf("remove() did not collapse the remaining bucket: %+v", n.slots)
+	}
+	if _, ok := n.remove(same, "missing", 0); ok {
+		t.Error("remove() of a missing key reported success")
+	}
+	n, _ = n.remove(same, "a", 0)
+	n, _ = n.remove(same, "bb", 0)
+	if n != nil {
+		t.Errorf("remove() of all keys left %+v", n)
+	}
+}
+
+func BenchmarkImmutableMap_Set(b *testing.B) {
+	im := NewImmutable[int, int]()
+	for i := range 100000 {
+		im = im.Set(i, i)
+	}
+	b.ResetTimer()
+	for i := range b.N {
+		_ = im.Set(i%200000, i)
+	}
+}
@@ -0,0 +1,305 @@
+package omap
+
+import (
+	"iter"
+	"math/rand/v2"
+	"strconv"
+)
+
+// node is a node of an implicit treap that mirrors the order of a list.
+// The in-order traversal of the treap yields the elements in list order,
+// which allows positional lookups and rank queries in O(log n).
+type node[K comparable, V any] struct {
+	left, right, parent *node[K, V]
+	elem                *Element[K, V]
+	size                int
+	prio                uint32
+}
+
+func (n *node[K, V]) len() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// update recomputes the size of n and fixes the parent links of its children.
+func (n *node[K, V]) update() {
+	n.size = 1 + n.left.len() + n.right.len()
+	if n.left != nil {
+		n.left.parent = n
+	}
+	if n.right != nil {
+		n.right.parent = n
+	}
+}
+
+// index is an order-statistics index over a list. It is built lazily on the
+// first positional query and then maintained by the list operations.
+type index[K comparable, V any] struct {
+	root *node[K, V]
+}
+
+func merge[K comparable, V any](a, b *node[K, V]) *node[K, V] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if a.prio > b.prio {
+		a.right = merge(a.right, b)
+		a.update()
+		return a
+	}
+	b.left = merge(a, b.left)
+	b.update()
+	return b
+}
+
+// split splits the treap into the first k nodes and the rest.
+func split[K comparable, V any](n *node[K, V], k int) (*node[K, V], *node[K, V]) {
+	if n == nil {
+		return nil, nil
+	}
+	if n.left.len() >= k {
+		l, r := split(n.left, k)
+		n.left = r
+		n.update()
+		if l != nil {
+			l.parent = nil
+		}
+		return l, n
+	}
+	l, r := split(n.right, k-n.left.len()-1)
+	n.right = l
+	n.update()
+	if r != nil {
+		r.parent = nil
+	}
+	return n, r
+}
+
+// build creates the index for the elements of l in O(n).
+func (x *index[K, V]) build(l *list[K, V]) {
+	x.root = buildNodes(l.root.next, &l.root)
+}
+
+// buildNodes creates a treap for the elements from first up to, but not including, stop in O(n).
+func buildNodes[K comparable, V any](first, stop *Element[K, V]) *node[K, V] {
+	var stack []*node[K, V]
+	for e := first; e != stop; e = e.next {
+		n := &node[K, V]{elem: e, size: 1, prio: rand.Uint32()}
+		e.node = n
+
+		var last *node[K, V]
+		for len(stack) > 0 && stack[len(stack)-1].prio < n.prio {
+			last = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+		}
+		n.left = last
+		if last != nil {
+			last.parent = n
+		}
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			top.right = n
+			n.parent = top
+		}
+		stack = append(stack, n)
+	}
+
+	if len(stack) == 0 {
+		return nil
+	}
+	root := stack[0]
+	root.parent = nil
+	fixSizes(root)
+	return root
+}
+
+func fixSizes[K comparable, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+	n.size = 1 + fixSizes(n.left) + fixSizes(n.right)
+	return n.size
+}
+
+// rank returns the position of n in the index.
+func (x *index[K, V]) rank(n *node[K, V]) int {
+	r := n.left.len()
+	for ; n.parent != nil; n = n.parent {
+		if n == n.parent.right {
+			r += n.parent.left.len() + 1
+		}
+	}
+	return r
+}
+
+// at returns the node at position i.
+func (x *index[K, V]) at(i int) *node[K, V] {
+	n := x.root
+	for n != nil {
+		switch l := n.left.len(); {
+		case i < l:
+			n = n.left
+		case i == l:
+			return n
+		default:
+			i -= l + 1
+			n = n.right
+		}
+	}
+	return nil
+}
+
+// insert adds e to the index at position i.
+func (x *index[K, V]) insert(e *Element[K, V], i int) {
+	n := &node[K, V]{elem: e, size: 1, prio: rand.Uint32()}
+	e.node = n
+	l, r := split(x.root, i)
+	x.root = merge(merge(l, n), r)
+	x.root.parent = nil
+}
+
+// remove deletes e from the index.
+func (x *index[K, V]) remove(e *Element[K, V]) {
+	n := e.node
+	e.node = nil
+
+	c := merge(n.left, n.right)
+	p := n.parent
+	if c != nil {
+		c.parent = p
+	}
+	switch {
+	case p == nil:
+		x.root = c
+	case p.left == n:
+		p.left = c
+	default:
+		p.right = c
+	}
+	for ; p != nil; p = p.parent {
+		p.size--
+	}
+}
+
+// removeRun deletes the n nodes starting with the node of e from the index in O(log n)
+// and returns them as a separate treap.
+func (x *index[K, V]) removeRun(e *Element[K, V], n int) *node[K, V] {
+	i := x.rank(e.node)
+	l, r := split(x.root, i)
+	run, r := split(r, n)
+	x.root = merge(l, r)
+	if x.root != nil {
+		x.root.parent = nil
+	}
+	return run
+}
+
+// append adds the nodes of the treap run after the last node of the index in O(log n).
+func (x *index[K, V]) append(run *node[K, V]) {
+	x.root = merge(x.root, run)
+	if x.root != nil {
+		x.root.parent = nil
+	}
+}
+
+// position returns the position that an element inserted after at would take.
+func (l *list[K, V]) position(at *Element[K, V]) int {
+	if at == &l.root {
+		return 0
+	}
+	return l.idx.rank(at.node) + 1
+}
+
+// ensureIndex builds the order-statistics index if it does not exist yet.
+func (l *list[K, V]) ensureIndex() *index[K, V] {
+	if l.idx == nil {
+		l.check()
+		l.idx = &index[K, V]{}
+		l.idx.build(l)
+	}
+	return l.idx
+}
+
+func checkIndex(i, n int) {
+	if i < 0 || i >= n {
+		panic("omap: index out of range [" + strconv.Itoa(i) + "] with length " + strconv.Itoa(n))
+	}
+}
+
+func checkSlice(i, j, n int) {
+	if i < 0 || j < i || j > n {
+		panic("omap: slice bounds out of range [" + strconv.Itoa(i) + ":" + strconv.Itoa(j) + "] with length " + strconv.Itoa(n))
+	}
+}
+
+// At returns the key-value pair at position i in O(log n).
+// It panics if i is out of range.
+//
+// Although it only looks up an entry, the first positional call builds the
+// order-statistics index and stores it in the map, so it modifies the map and must
+// not run concurrently with other readers. The index is kept from then on, which
+// makes Set, Delete and moves take O(log n) instead of O(1), until the map is
+// sorted or otherwise reordered.
+func (m *Map[K, V]) At(i int) (K, V) {
+	checkIndex(i, m.Len())
+	e := m.kl.ensureIndex().at(i).elem
+	return e.key, e.val
+}
+
+// IndexOf returns the position of the key in O(log n), or -1 if the key does not exist.
+// Like At, it builds the index on first use, so it modifies the map.
+func (m *Map[K, V]) IndexOf(key K) int {
+	e, ok := m.kv[key]
+	if !ok {
+		return -1
+	}
+	return m.kl.ensureIndex().rank(e.node)
+}
+
+// InsertAt inserts a key-value pair at position i in O(log n), shifting the following entries.
+// It returns false if the key already exists. It panics if i is not in the range [0, Len()].
+func (m *Map[K, V]) InsertAt(i int, key K, value V) bool {
+	checkIndex(i, m.Len()+1)
+	m.lazyInit()
+	if _, exists := m.kv[key]; exists {
+		return false
+	}
+	at := &m.kl.root
+	if i > 0 {
+		at = m.kl.ensureIndex().at(i - 1).elem
+	}
+	m.kv[key] = m.kl.insert(&Element[K, V]{key: key, val: value}, at)
+	return true
+}
+
+// DeleteAt removes the key-value pair at position i in O(log n) and returns it.
+// It panics if i is out of range.
+func (m *Map[K, V]) DeleteAt(i int) (K, V) {
+	checkIndex(i, m.Len())
+	e := m.kl.ensureIndex().at(i).elem
+	m.kl.delete(e)
+	delete(m.kv, e.key)
+	return e.key, e.val
+}
+
+// Slice returns an iterator over the entries in positions [i, j).
+// Locating the first entry takes O(log n). It panics if the range is invalid.
+// Like At, it builds the index on first use, so it modifies the map.
+func (m *Map[K, V]) Slice(i, j int) iter.Seq2[K, V] {
+	checkSlice(i, j, m.Len())
+	return func(yield func(K, V) bool) {
+		if i == j || i >= m.Len() {
+			return
+		}
+		n := j - i
+		m.walk(m.kl.ensureIndex().at(i).elem, false, func(e *Element[K, V]) bool {
+			n--
+			return yield(e.key, e.val) && n > 0
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package omap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestMap_At(t *testing.T) {
+	m := newABC()
+
+	for i, want := range []string{"a", "b", "c"} {
+		k, v := m.At(i)
+		if k != want || v != i+1 {
+			t.Errorf("At(%d) = (%v, %v), want (%v, %v)", i, k, v, want, i+1)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("At(3) should panic")
+		}
+	}()
+	m.At(3)
+}
+
+func TestMap_IndexOf(t *testing.T) {
+	m := newABC()
+
+	for i, k := range []string{"a", "b", "c"} {
+		if got := m.IndexOf(k); got != i {
+			t.Errorf("IndexOf(%s) = %d, want %d", k, got, i)
+		}
+	}
+	if got := m.IndexOf("missing"); got != -1 {
+		t.Errorf("IndexOf(missing) = %d, want -1", got)
+	}
+
+	// The index follows positional changes
+	m.MoveToFront("c")
+	m.Set("d", 4)
+	m.Delete("a")
+	for i, k := range []string{"c", "b", "d"} {
+		if got := m.IndexOf(k); got != i {
+			t.Errorf("IndexOf(%s) after changes = %d, want %d", k, got, i)
+		}
+	}
+
+	// and is rebuilt after bulk reordering
+	m.Reverse()
+	for i, k := range []string{"d", "b", "c"} {
+		if got := m.IndexOf(k); got != i {
+			t.Errorf("IndexOf(%s) after Reverse = %d, want %d", k, got, i)
+		}
+	}
+}
+
+func TestMap_InsertAt(t *testing.T) {
+	var m Map[string, int]
+
+	if !m.InsertAt(0, "b", 2) {
+		t.Error("InsertAt(0, b) on zero map should succeed")
+	}
+	m.InsertAt(0, "a", 1)
+	m.InsertAt(2, "d", 4)
+	m.InsertAt(2, "c", 3)
+
+	if want := []string{"a", "b", "c", "d"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+	if m.InsertAt(1, "c", 0) {
+		t.Error("InsertAt with existing key should fail")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("InsertAt(5) should panic")
+		}
+	}()
+	m.InsertAt(5, "x", 0)
+}
+
+func TestMap_DeleteAt(t *testing.T) {
+	m := newABC()
+
+	k, v := m.DeleteAt(1)
+	if k != "b" || v != 2 {
+		t.Errorf("DeleteAt(1) = (%v, %v), want (b, 2)", k, v)
+	}
+	if want := []string{"a", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+	if m.Has("b") {
+		t.Error("Has(b) after DeleteAt = true, want false")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("DeleteAt(-1) should panic")
+		}
+	}()
+	m.DeleteAt(-1)
+}
+
+func TestMap_Slice(t *testing.T) {
+	m := New[int, int]()
+	for i := range 10 {
+		m.Set(i, i*i)
+	}
+
+	var keys []int
+	for k, v := range m.Slice(3, 6) {
+		if v != k*k {
+			t.Errorf("Slice value for %d = %d, want %d", k, v, k*k)
+		}
+		keys = append(keys, k)
+	}
+	if want := []int{3, 4, 5}; !slices.Equal(keys, want) {
+		t.Errorf("Slice(3, 6) = %v, want %v", keys, want)
+	}
+
+	for range m.Slice(10, 10) {
+		t.Error("Slice(10, 10) should be empty")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Slice(5, 11) should panic")
+		}
+	}()
+	m.Slice(5, 11)
+}
+
+func TestMap_IndexRandomized(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+	m := New[int, int]()
+	var model []int
+
+	for i := range 5000 {
+		switch op := r.IntN(6); {
+		case op == 0 || len(model) == 0:
+			pos := r.IntN(len(model) + 1)
+			m.InsertAt(pos, i, i)
+			model = slices.Insert(model, pos, i)
+		case op == 1:
+			m.Set(i, i)
+			model = append(model, i)
+		case op == 2:
+			pos := r.IntN(len(model))
+			k, _ := m.DeleteAt(pos)
+			if k != model[pos] {
+				t.Fatalf("DeleteAt(%d) = %d, want %d", pos, k, model[pos])
+			}
+			model = slices.Delete(model, pos, pos+1)
+		case op == 3:
+			k := model[r.IntN(len(model))]
+			m.Delete(k)
+			model = slices.DeleteFunc(model, func(x int) bool { return x == k })
+		case op == 4:
+			a, b := model[r.IntN(len(model))], model[r.IntN(len(model))]
+			m.MoveAfter(a, b)
+			if a != b {
+				model = slices.DeleteFunc(model, func(x int) bool { return x == a })
+				pos := slices.Index(model, b)
+				model = slices.Insert(model, pos+1, a)
+			}
+		default:
+			pos := r.IntN(len(model))
+			if k, _ := m.At(pos); k != model[pos] {
+				t.Fatalf("At(%d) = %d, want %d", pos, k, model[pos])
+			}
+			if got := m.IndexOf(model[pos]); got != pos {
+				t.Fatalf("IndexOf(%d) = %d, want %d", model[pos], got, pos)
+			}
+		}
+	}
+
+	if !slices.Equal(m.Keys(), model) {
+		t.Fatalf("Keys() diverged from model")
+	}
+	for i, k := range model {
+		if got := m.IndexOf(k); got != i {
+			t.Fatalf("IndexOf(%d) = %d, want %d", k, got, i)
+		}
+	}
+}
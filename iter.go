@@ -0,0 +1,183 @@
+package omap
+
+import "iter"
+
+// Entry is a key-value pair of a Map.
+type Entry[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// walk calls yield for each element starting at e and moving towards the back,
+// or towards the front if backward is set, until yield returns false.
+//
+// yield may delete and move elements. walk marks each element it produces, and
+// resumes at the next element that it has not produced yet, so each element is
+// produced exactly once as long as it stays in the list. Elements that were moved
+// behind the current one before they were produced are picked up by another pass
+// from the start. walk panics if the list is changed in any other way while yield runs.
+//
+// Any number of walks may run at once while nothing writes to the map. Only a walk
+// that starts while no other walk runs marks the elements, so no two walks write
+// them at once; the others remember the elements they produce in a set of their own.
+func (m *Map[K, V]) walk(e *Element[K, V], backward bool, yield func(e *Element[K, V]) bool) {
+	l := &m.kl
+	if e == nil || e == &l.root {
+		return
+	}
+	l.check()
+	alone := l.walks.Add(1) == 1
+	defer l.walks.Add(-1)
+
+	step := func(e *Element[K, V]) *Element[K, V] {
+		if backward {
+			return e.prev
+		}
+		return e.next
+	}
+	id := l.walkID.Add(1)
+	// produced holds the elements produced by a walk that does not run alone
+	var produced map[*Element[K, V]]bool
+	start, fromEnd := e, e == step(&l.root)
+	mod, moves := l.mod, l.moves
+	for {
+		pass := l.moves
+		for e != &l.root {
+			if alone {
+				e.seen = id
+			} else {
+				produced = markProduced(produced, e)
+			}
+			next, before := step(e), l.moves
+			if !yield(e) {
+				return
+			}
+			if l.mod != mod {
+				panic("omap: map modified during iteration")
+			}
+
+			// resume after e, unless it was deleted or moved; deleted elements keep
+			// the links they had, which lead back into the list
+			if e.list == l && e.moved <= before {
+				next = step(e)
+			}
+			for next != &l.root && (next.list != l || l.moves != moves && (next.seen == id || produced[next])) {
+				next = step(next)
+			}
+			e = next
+		}
+
+		// start over if elements were moved, since some may have been moved behind
+		switch {
+		case l.moves == pass:
+			return
+		case fromEnd:
+			e = step(&l.root)
+		case start.list == l:
+			e = start
+		default:
+			return
+		}
+		for e != &l.root && (e.seen == id || produced[e]) {
+			e = step(e)
+		}
+	}
+}
+
+// markProduced adds e to the set of produced elements of a walk that does not run alone.
+func markProduced[K comparable, V any](produced map[*Element[K, V]]bool, e *Element[K, V]) map[*Element[K, V]]bool {
+	if produced == nil {
+		produced = make(map[*Element[K, V]]bool)
+	}
+	produced[e] = true
+	return produced
+}
+
+// Backward returns an iterator over the map's entries in reverse insertion order.
+func (m *Map[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.walk(m.kl.root.prev, true, func(e *Element[K, V]) bool {
+			return yield(e.key, e.val)
+		})
+	}
+}
+
+// KeysSeq returns an iterator over the map's keys in insertion order.
+func (m *Map[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.walk(m.kl.root.next, false, func(e *Element[K, V]) bool {
+			return yield(e.key)
+		})
+	}
+}
+
+// ValuesSeq returns an iterator over the map's values in insertion order.
+func (m *Map[K, V]) ValuesSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		m.walk(m.kl.root.next, false, func(e *Element[K, V]) bool {
+			return yield(e.val)
+		})
+	}
+}
+
+// Enumerate returns an iterator over the map's entries in insertion order, paired with their positions.
+func (m *Map[K, V]) Enumerate() iter.Seq2[int, Entry[K, V]] {
+	return func(yield func(int, Entry[K, V]) bool) {
+		i := 0
+		m.walk(m.kl.root.next, false, func(e *Element[K, V]) bool {
+			if !yield(i, Entry[K, V]{Key: e.key, Value: e.val}) {
+				return false
+			}
+			i++
+			return true
+		})
+	}
+}
+
+// From returns an iterator over the entries starting at the given key through the end of the map.
+// The iteration is empty if the key does not exist.
+func (m *Map[K, V]) From(key K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		m.walk(m.kv[key], false, func(e *Element[K, V]) bool {
+			return yield(e.key, e.val)
+		})
+	}
+}
+
+// Between returns an iterator over the entries from the key from through the key to, inclusive.
+// The iteration is empty if either key does not exist or to precedes from, like in DeleteRange.
+// It ends early if the key to is deleted or moved inside the loop, and it never produces more
+// entries than the range held when it started.
+func (m *Map[K, V]) Between(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		first, last, n := m.between(from, to)
+		moves := m.kl.moves
+		m.walk(first, false, func(e *Element[K, V]) bool {
+			n--
+			return yield(e.key, e.val) && e != last && n > 0 && last.list == &m.kl && last.moved <= moves
+		})
+	}
+}
+
+// between returns the elements of the keys from and to along with the number of
+// elements from first through last, or nils if either key does not exist or to
+// precedes from.
+func (m *Map[K, V]) between(from, to K) (first, last *Element[K, V], n int) {
+	first, ok := m.kv[from]
+	if !ok {
+		return nil, nil, 0
+	}
+	last, ok = m.kv[to]
+	if !ok {
+		return nil, nil, 0
+	}
+
+	n = 1
+	for e := first; e != last; e = e.next {
+		if e == &m.kl.root {
+			return nil, nil, 0
+		}
+		n++
+	}
+	return first, last, n
+}
@@ -0,0 +1,130 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+)
+
+func collectKeys[K comparable, V any](seq func(func(K, V) bool)) []K {
+	var keys []K
+	for k := range seq {
+		keys = append(keys, k)
+	}
+	return keys
+}
+
+func TestMap_Backward(t *testing.T) {
+	m := newABC()
+
+	if got, want := collectKeys(m.Backward()), []string{"c", "b", "a"}; !slices.Equal(got, want) {
+		t.Errorf("Backward() = %v, want %v", got, want)
+	}
+
+	// Early break
+	var keys []string
+	for k := range m.Backward() {
+		keys = append(keys, k)
+		if k == "b" {
+			break
+		}
+	}
+	if want := []string{"c", "b"}; !slices.Equal(keys, want) {
+		t.Errorf("Backward() with break = %v, want %v", keys, want)
+	}
+
+	var zero Map[string, int]
+	if got := collectKeys(zero.Backward()); len(got) != 0 {
+		t.Errorf("Backward() on zero map = %v, want empty", got)
+	}
+}
+
+func TestMap_KeysSeq_ValuesSeq(t *testing.T) {
+	m := newABC()
+
+	if got, want := slices.Collect(m.KeysSeq()), []string{"a", "b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("KeysSeq() = %v, want %v", got, want)
+	}
+	if got, want := slices.Collect(m.ValuesSeq()), []int{1, 2, 3}; !slices.Equal(got, want) {
+		t.Errorf("ValuesSeq() = %v, want %v", got, want)
+	}
+}
+
+func TestMap_Enumerate(t *testing.T) {
+	m := newABC()
+
+	want := []Entry[string, int]{{"a", 1}, {"b", 2}, {"c", 3}}
+	var n int
+	for i, e := range m.Enumerate() {
+		if i != n {
+			t.Errorf("Enumerate() index = %d, want %d", i, n)
+		}
+		if e != want[i] {
+			t.Errorf("Enumerate() entry %d = %v, want %v", i, e, want[i])
+		}
+		n++
+	}
+	if n != len(want) {
+		t.Errorf("Enumerate() count = %d, want %d", n, len(want))
+	}
+}
+
+func TestMap_From(t *testing.T) {
+	m := newABC()
+
+	if got, want := collectKeys(m.From("b")), []string{"b", "c"}; !slices.Equal(got, want) {
+		t.Errorf("From(b) = %v, want %v", got, want)
+	}
+	if got := collectKeys(m.From("missing")); len(got) != 0 {
+		t.Errorf("From(missing) = %v, want empty", got)
+	}
+}
+
+func TestMap_Between(t *testing.T) {
+	m := newABC()
+	m.Set("d", 4)
+
+	tests := []struct {
+		name     string
+		from, to string
+		want     []string
+	}{
+		{"Middle", "b", "c", []string{"b", "c"}},
+		{"All", "a", "d", []string{"a", "b", "c", "d"}},
+		{"Single", "c", "c", []string{"c"}},
+		{"Reversed bounds", "c", "a", nil},
+		{"Missing from", "x", "c", nil},
+		{"Missing to", "a", "x", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := collectKeys(m.Between(tt.from, tt.to)); !slices.Equal(got, tt.want) {
+				t.Errorf("Between(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("Modify to", func(t *testing.T) {
+		modify := map[string]func(m *Map[string, int]){
+			"Delete":      func(m *Map[string, int]) { m.Delete("d") },
+			"MoveToFront": func(m *Map[string, int]) { m.MoveToFront("d") },
+			"MoveToBack":  func(m *Map[string, int]) { m.MoveToBack("d") },
+		}
+		for name, fn := range modify {
+			m := New[string, int]()
+			for i, k := range []string{"a", "b", "c", "d", "e", "f"} {
+				m.Set(k, i)
+			}
+			var got []string
+			for k := range m.Between("b", "d") {
+				got = append(got, k)
+				if k == "b" {
+					fn(m)
+				}
+			}
+			if want := []string{"b"}; !slices.Equal(got, want) {
+				t.Errorf("%s: Between(b, d) = %v, want %v", name, got, want)
+			}
+		}
+	})
+}
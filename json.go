@@ -12,6 +12,79 @@ import (
 
 // MarshalJSON handles JSON marshaling for the Map.
 func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(m)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the Map.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	return UnmarshalJSON(data, m)
+}
+
+// MarshalJSON handles JSON marshaling for the SortedMap.
+func (s *SortedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(s)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the SortedMap.
+func (s *SortedMap[K, V]) UnmarshalJSON(data []byte) error {
+	return UnmarshalJSON(data, s)
+}
+
+// MarshalJSON handles JSON marshaling for the SyncMap, using a snapshot of the entries.
+func (s *SyncMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(s)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the SyncMap.
+// The members are decoded first and then set under a single lock.
+func (s *SyncMap[K, V]) UnmarshalJSON(data []byte) error {
+	m := New[K, V]()
+	if err := UnmarshalJSON(data, m); err != nil {
+		return err
+	}
+	s.Merge(m)
+	return nil
+}
+
+// MarshalJSON handles JSON marshaling for the COWMap, using the current version.
+func (c *COWMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the COWMap.
+// The members are decoded first and then published as a single new version.
+func (c *COWMap[K, V]) UnmarshalJSON(data []byte) error {
+	m := New[K, V]()
+	if err := UnmarshalJSON(data, m); err != nil {
+		return err
+	}
+	c.Update(func(cur *Map[K, V]) {
+		cur.Merge(m)
+	})
+	return nil
+}
+
+// MarshalJSON handles JSON marshaling for the ShardedMap, using a consistent cut.
+func (s *ShardedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(s)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the ShardedMap.
+// The members are decoded first and then set one by one.
+func (s *ShardedMap[K, V]) UnmarshalJSON(data []byte) error {
+	m := New[K, V]()
+	if err := UnmarshalJSON(data, m); err != nil {
+		return err
+	}
+	for k, v := range m.All() {
+		s.Set(k, v)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the entries of m as a JSON object, preserving their order.
+// It helps implementations of OrderedMap to implement json.Marshaler.
+func MarshalJSON[K comparable, V any](m OrderedMap[K, V]) ([]byte, error) {
 	buf := bytes.Buffer{}
 	buf.WriteByte('{')
 
@@ -46,8 +119,9 @@ func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
-// UnmarshalJSON handles JSON unmarshaling for the Map.
-func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+// UnmarshalJSON decodes a JSON object and sets its members in m in order.
+// It helps implementations of OrderedMap to implement json.Unmarshaler.
+func UnmarshalJSON[K comparable, V any](data []byte, m OrderedMap[K, V]) error {
 	if !bytes.HasPrefix(data, []byte{'{'}) {
 		return errors.New("expected JSON object")
 	}
@@ -96,3 +96,32 @@ func TestMap_UnmarshalJSON(t *testing.T) {
 		}
 	})
 }
+
+func TestSortedMap_JSON(t *testing.T) {
+	s := NewSorted[string, int]()
+	if err := json.Unmarshal([]byte(`{"c":3,"a":1,"b":2}`), s); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(s.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", s.Keys(), want)
+	}
+
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("MarshalJSON failed: %v", err)
+	}
+	if expected := `{"a":1,"b":2,"c":3}`; string(b) != expected {
+		t.Errorf("MarshalJSON = %s, want %s", string(b), expected)
+	}
+
+	// Zero value in a struct field
+	var v struct {
+		M SortedMap[int, string] `json:"m"`
+	}
+	if err := json.Unmarshal([]byte(`{"m":{"2":"two","1":"one"}}`), &v); err != nil {
+		t.Fatalf("UnmarshalJSON failed: %v", err)
+	}
+	if want := []int{1, 2}; !slices.Equal(v.M.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", v.M.Keys(), want)
+	}
+}
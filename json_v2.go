@@ -11,16 +11,150 @@ import (
 
 // MarshalJSON handles JSON marshaling for the Map.
 func (m *Map[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(m)
+}
+
+// MarshalJSONTo encodes the Map into JSON using the provided encoder.
+func (m *Map[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return MarshalJSONTo(enc, m)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the Map.
+func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
+	return UnmarshalJSON(data, m)
+}
+
+// UnmarshalJSONFrom decodes JSON data into the Map using the provided decoder.
+func (m *Map[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return UnmarshalJSONFrom(dec, m)
+}
+
+// MarshalJSON handles JSON marshaling for the SortedMap.
+func (s *SortedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(s)
+}
+
+// MarshalJSONTo encodes the SortedMap into JSON using the provided encoder.
+func (s *SortedMap[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return MarshalJSONTo(enc, s)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the SortedMap.
+func (s *SortedMap[K, V]) UnmarshalJSON(data []byte) error {
+	return UnmarshalJSON(data, s)
+}
+
+// UnmarshalJSONFrom decodes JSON data into the SortedMap using the provided decoder.
+func (s *SortedMap[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	return UnmarshalJSONFrom(dec, s)
+}
+
+// MarshalJSON handles JSON marshaling for the SyncMap, using a snapshot of the entries.
+func (s *SyncMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(s)
+}
+
+// MarshalJSONTo encodes a snapshot of the SyncMap into JSON using the provided encoder.
+func (s *SyncMap[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return MarshalJSONTo(enc, s)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the SyncMap.
+func (s *SyncMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := jsontext.NewDecoder(bytes.NewReader(data))
+	return s.UnmarshalJSONFrom(dec)
+}
+
+// UnmarshalJSONFrom decodes JSON data into the SyncMap using the provided decoder.
+// The members are decoded first and then set under a single lock.
+func (s *SyncMap[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	m := New[K, V]()
+	if err := UnmarshalJSONFrom(dec, m); err != nil {
+		return err
+	}
+	s.Merge(m)
+	return nil
+}
+
+// MarshalJSON handles JSON marshaling for the COWMap, using the current version.
+func (c *COWMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(c)
+}
+
+// MarshalJSONTo encodes the current version of the COWMap into JSON using the provided encoder.
+func (c *COWMap[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return MarshalJSONTo(enc, c)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the COWMap.
+func (c *COWMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := jsontext.NewDecoder(bytes.NewReader(data))
+	return c.UnmarshalJSONFrom(dec)
+}
+
+// UnmarshalJSONFrom decodes JSON data into the COWMap using the provided decoder.
+// The members are decoded first and then published as a single new version.
+func (c *COWMap[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	m := New[K, V]()
+	if err := UnmarshalJSONFrom(dec, m); err != nil {
+		return err
+	}
+	c.Update(func(cur *Map[K, V]) {
+		cur.Merge(m)
+	})
+	return nil
+}
+
+// MarshalJSON handles JSON marshaling for the ShardedMap, using a consistent cut.
+func (s *ShardedMap[K, V]) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(s)
+}
+
+// MarshalJSONTo encodes a consistent cut of the ShardedMap into JSON using the provided encoder.
+func (s *ShardedMap[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+	return MarshalJSONTo(enc, s)
+}
+
+// UnmarshalJSON handles JSON unmarshaling for the ShardedMap.
+func (s *ShardedMap[K, V]) UnmarshalJSON(data []byte) error {
+	dec := jsontext.NewDecoder(bytes.NewReader(data))
+	return s.UnmarshalJSONFrom(dec)
+}
+
+// UnmarshalJSONFrom decodes JSON data into the ShardedMap using the provided decoder.
+// The members are decoded first and then set one by one.
+func (s *ShardedMap[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+	m := New[K, V]()
+	if err := UnmarshalJSONFrom(dec, m); err != nil {
+		return err
+	}
+	for k, v := range m.All() {
+		s.Set(k, v)
+	}
+	return nil
+}
+
+// MarshalJSON encodes the entries of m as a JSON object, preserving their order.
+// It helps implementations of OrderedMap to implement json.Marshaler.
+func MarshalJSON[K comparable, V any](m OrderedMap[K, V]) ([]byte, error) {
 	buf := bytes.Buffer{}
 	enc := jsontext.NewEncoder(&buf)
-	if err := m.MarshalJSONTo(enc); err != nil {
+	if err := MarshalJSONTo(enc, m); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
 }
 
-// MarshalJSONTo encodes the Map into JSON using the provided encoder.
-func (m *Map[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
+// UnmarshalJSON decodes a JSON object and sets its members in m in order.
+// It helps implementations of OrderedMap to implement json.Unmarshaler.
+func UnmarshalJSON[K comparable, V any](data []byte, m OrderedMap[K, V]) error {
+	dec := jsontext.NewDecoder(bytes.NewReader(data))
+	return UnmarshalJSONFrom(dec, m)
+}
+
+// MarshalJSONTo encodes the entries of m as a JSON object using the provided encoder,
+// preserving their order.
+func MarshalJSONTo[K comparable, V any](enc *jsontext.Encoder, m OrderedMap[K, V]) error {
 	if err := enc.WriteToken(jsontext.BeginObject); err != nil {
 		return err
 	}
@@ -44,14 +178,9 @@ func (m *Map[K, V]) MarshalJSONTo(enc *jsontext.Encoder) error {
 	return nil
 }
 
-// UnmarshalJSON handles JSON unmarshaling for the Map.
-func (m *Map[K, V]) UnmarshalJSON(data []byte) error {
-	dec := jsontext.NewDecoder(bytes.NewReader(data))
-	return m.UnmarshalJSONFrom(dec)
-}
-
-// UnmarshalJSONFrom decodes JSON data into the Map using the provided decoder.
-func (m *Map[K, V]) UnmarshalJSONFrom(dec *jsontext.Decoder) error {
+// UnmarshalJSONFrom decodes a JSON object using the provided decoder and sets its
+// members in m in order.
+func UnmarshalJSONFrom[K comparable, V any](dec *jsontext.Decoder, m OrderedMap[K, V]) error {
 	if kind := dec.PeekKind(); kind != '{' {
 		return fmt.Errorf("expected object")
 	}
@@ -1,33 +1,220 @@
 package omap
 
-type elem[K comparable, V any] struct {
-	next, prev *elem[K, V]
+import "sync/atomic"
+
+// Element is an entry in a Map. It is a handle to a key-value pair that
+// allows walking to neighbouring entries without further key lookups.
+type Element[K comparable, V any] struct {
+	next, prev *Element[K, V]
+	list       *list[K, V]
+	node       *node[K, V]
+	moved      uint   // the moves count of the list after the element was last moved
+	seen       uint64 // the id of the last walk that marked the element as produced
 	key        K
 	val        V
 }
 
-// list is a doubly linked list. It stores elems in insertion order.
+// Key returns the key of the element.
+func (e *Element[K, V]) Key() K {
+	return e.key
+}
+
+// Value returns the value of the element.
+func (e *Element[K, V]) Value() V {
+	return e.val
+}
+
+// SetValue updates the value of the element in place.
+func (e *Element[K, V]) SetValue(value V) {
+	e.val = value
+}
+
+// Next returns the next element or nil.
+func (e *Element[K, V]) Next() *Element[K, V] {
+	if p := e.next; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+// Prev returns the previous element or nil.
+func (e *Element[K, V]) Prev() *Element[K, V] {
+	if p := e.prev; e.list != nil && p != &e.list.root {
+		return p
+	}
+	return nil
+}
+
+// list is a doubly linked list. It stores elements in insertion order.
+//
+// Elements deleted while a walk is running keep their links, so that the walk can
+// step from a deleted element back into the list. Otherwise the links are cleared,
+// so that a deleted element does not keep its neighbours alive.
 type list[K comparable, V any] struct {
-	root elem[K, V]
+	root   Element[K, V]
+	idx    *index[K, V]
+	mod    uint          // counts structural changes other than deletes and moves
+	moves  uint          // counts moves
+	walks  atomic.Int32  // the number of running walks
+	walkID atomic.Uint64 // the id of the last walk that started
+}
+
+// check panics if the list was copied by value after its first use.
+// In that case the elements still point back to the root of the original list.
+func (l *list[K, V]) check() {
+	if l.root.next != nil && l.root.next.prev != &l.root {
+		panic("omap: use of a Map copied by value")
+	}
 }
 
 func (l *list[K, V]) init() {
 	l.root.next = &l.root
 	l.root.prev = &l.root
+	l.idx = nil
+	l.mod++
+}
+
+// reordered must be called after the list is relinked in bulk.
+// It drops the order-statistics index, which is rebuilt on demand.
+func (l *list[K, V]) reordered() {
+	l.mod++
+	if l.idx == nil {
+		return
+	}
+	l.idx = nil
+	for e := l.root.next; e != &l.root; e = e.next {
+		e.node = nil
+	}
+}
+
+func (l *list[K, V]) append(k K, v V) *Element[K, V] {
+	return l.insert(&Element[K, V]{key: k, val: v}, l.root.prev)
 }
 
-func (l *list[K, V]) append(k K, v V) *elem[K, V] {
-	e := &elem[K, V]{key: k, val: v}
-	e.prev = l.root.prev
-	e.next = &l.root
-	l.root.prev.next = e
-	l.root.prev = e
+// insert inserts e after at.
+func (l *list[K, V]) insert(e, at *Element[K, V]) *Element[K, V] {
+	l.check()
+	if l.idx != nil {
+		l.idx.insert(e, l.position(at))
+	}
+	l.mod++
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+	e.list = l
 	return e
 }
 
-func (l *list[K, V]) delete(e *elem[K, V]) {
+// move moves e to its new position after at.
+func (l *list[K, V]) move(e, at *Element[K, V]) {
+	if e == at || e.prev == at {
+		return
+	}
+	l.check()
+	l.moves++
+	e.moved = l.moves
+	e.prev.next = e.next
+	e.next.prev = e.prev
+	if l.idx != nil {
+		l.idx.remove(e)
+		l.idx.insert(e, l.position(at))
+	}
+
+	e.prev = at
+	e.next = at.next
+	e.prev.next = e
+	e.next.prev = e
+}
+
+// swap exchanges the positions of a and b.
+func (l *list[K, V]) swap(a, b *Element[K, V]) {
+	if a == b {
+		return
+	}
+	ap := a.prev
+	l.move(a, b)
+	if ap == b {
+		l.move(b, a)
+	} else {
+		l.move(b, ap)
+	}
+}
+
+// deleteRun unlinks the n elements from first through last, which must be contiguous.
+func (l *list[K, V]) deleteRun(first, last *Element[K, V], n int) {
+	l.check()
+	if l.idx != nil {
+		l.idx.removeRun(first, n)
+	}
+
+	prev, next := first.prev, last.next
+	prev.next = next
+	next.prev = prev
+
+	keep := l.walks.Load() > 0
+	for e := first; e != next; {
+		n := e.next
+		e.list = nil
+		e.node = nil
+		if !keep {
+			e.next = nil
+			e.prev = nil
+		}
+		e = n
+	}
+}
+
+// splice moves the n elements from first through last, which must be contiguous,
+// to the end of dst. The order-statistics index of l is split rather than rebuilt.
+func (l *list[K, V]) splice(first, last *Element[K, V], n int, dst *list[K, V]) {
+	l.check()
+	dst.check()
+	var run *node[K, V]
+	if l.idx != nil {
+		run = l.idx.removeRun(first, n)
+	}
+	l.mod++
+	first.prev.next = last.next
+	last.next.prev = first.prev
+
+	empty := dst.root.next == &dst.root
+	at := dst.root.prev
+	at.next = first
+	first.prev = at
+	last.next = &dst.root
+	dst.root.prev = last
+	dst.mod++
+
+	// an empty list without an index adopts the treap of the moved elements
+	keep := run != nil && (dst.idx != nil || empty)
+	for e := first; e != &dst.root; e = e.next {
+		e.list = dst
+		if !keep {
+			e.node = nil
+		}
+	}
+	switch {
+	case dst.idx != nil:
+		if run == nil {
+			run = buildNodes(first, &dst.root)
+		}
+		dst.idx.append(run)
+	case keep:
+		dst.idx = &index[K, V]{root: run}
+	}
+}
+
+func (l *list[K, V]) delete(e *Element[K, V]) {
+	l.check()
+	if l.idx != nil {
+		l.idx.remove(e)
+	}
 	e.prev.next = e.next
 	e.next.prev = e.prev
-	e.prev = nil
-	e.next = nil
+	if l.walks.Load() == 0 {
+		e.next = nil
+		e.prev = nil
+	}
+	e.list = nil
 }
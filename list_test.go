@@ -92,3 +92,87 @@ func TestList_delete(t *testing.T) {
 		t.Errorf("After deleting e1, root.prev should be root")
 	}
 }
+
+func TestList_deleteDuringWalk(t *testing.T) {
+	l := list[string, int]{}
+	l.init()
+	e1 := l.append("a", 1)
+	e2 := l.append("b", 2)
+	e3 := l.append("c", 3)
+
+	// Elements deleted during a walk keep their links to step back into the list
+	l.walks.Add(1)
+	l.delete(e2)
+	l.walks.Add(-1)
+	if e2.list != nil || e2.Next() != nil || e2.next != e3 || e2.prev != e1 {
+		t.Errorf("Element deleted during a walk should keep its links")
+	}
+}
+
+func TestElement_NextPrev(t *testing.T) {
+	l := list[string, int]{}
+	l.init()
+
+	e1 := l.append("a", 1)
+	e2 := l.append("b", 2)
+
+	if e1.Next() != e2 {
+		t.Errorf("e1.Next() should be e2")
+	}
+	if e2.Next() != nil {
+		t.Errorf("e2.Next() should be nil")
+	}
+	if e2.Prev() != e1 {
+		t.Errorf("e2.Prev() should be e1")
+	}
+	if e1.Prev() != nil {
+		t.Errorf("e1.Prev() should be nil")
+	}
+
+	l.delete(e1)
+	if e1.Next() != nil || e1.Prev() != nil {
+		t.Errorf("Deleted element should have no neighbours")
+	}
+}
+
+func TestElement_KeyValue(t *testing.T) {
+	l := list[string, int]{}
+	l.init()
+
+	e := l.append("a", 1)
+	if e.Key() != "a" || e.Value() != 1 {
+		t.Errorf("Element = (%v, %v), want (a, 1)", e.Key(), e.Value())
+	}
+
+	e.SetValue(2)
+	if e.Value() != 2 {
+		t.Errorf("Value() after SetValue = %v, want 2", e.Value())
+	}
+}
+
+func TestList_move(t *testing.T) {
+	l := list[string, int]{}
+	l.init()
+
+	e1 := l.append("a", 1)
+	e2 := l.append("b", 2)
+	e3 := l.append("c", 3)
+
+	// Move head after tail
+	l.move(e1, e3)
+	if l.root.next != e2 || l.root.prev != e1 {
+		t.Errorf("After moving e1 after e3, list should be e2, e3, e1")
+	}
+	if e3.next != e1 || e1.prev != e3 || e1.next != &l.root {
+		t.Errorf("e1 should be linked between e3 and root")
+	}
+
+	// Swap head and tail
+	l.swap(e2, e1)
+	if l.root.next != e1 || e1.next != e3 || e3.next != e2 || e2.next != &l.root {
+		t.Errorf("After swapping e2 and e1, list should be e1, e3, e2")
+	}
+	if l.root.prev != e2 || e2.prev != e3 || e3.prev != e1 || e1.prev != &l.root {
+		t.Errorf("prev links are inconsistent after swap")
+	}
+}
@@ -5,8 +5,11 @@ import (
 )
 
 // Map represents an ordered map that maintains elements in the order of their insertion.
+//
+// A Map must not be copied after first use; use Clone instead.
 type Map[K comparable, V any] struct {
-	kv map[K]*elem[K, V]
+	_  noCopy
+	kv map[K]*Element[K, V]
 	kl list[K, V]
 }
 
@@ -29,7 +32,7 @@ func (m *Map[K, V]) lazyInit() {
 }
 
 func (m *Map[K, V]) init(capacity int) {
-	m.kv = make(map[K]*elem[K, V], capacity)
+	m.kv = make(map[K]*Element[K, V], capacity)
 	m.kl.init()
 }
 
@@ -38,6 +41,7 @@ func (m *Map[K, V]) init(capacity int) {
 // If the key does not exist, it is appended to the end of the insertion order list.
 func (m *Map[K, V]) Set(key K, value V) {
 	m.lazyInit()
+	m.kl.check()
 	if e, exists := m.kv[key]; exists {
 		e.val = value
 	} else {
@@ -50,6 +54,7 @@ func (m *Map[K, V]) Set(key K, value V) {
 // It returns true if the key-value pair was added, and false if the key already exists.
 func (m *Map[K, V]) TrySet(key K, value V) bool {
 	m.lazyInit()
+	m.kl.check()
 	if e, exists := m.kv[key]; !exists {
 		e = m.kl.append(key, value)
 		m.kv[key] = e
@@ -100,15 +105,22 @@ func (m *Map[K, V]) Clear() {
 }
 
 // All returns an iterator over the map's entries in insertion order.
+//
+// Entries may be deleted or moved while iterating. The iteration resumes at the
+// next entry that it has not produced yet, even if the current entry was deleted
+// or moved, so every entry that is not deleted is produced exactly once. Entries
+// that were moved behind the current one before they were produced are produced
+// after the others. Any other structural change during iteration, such as
+// inserting a key, sorting or clearing the map, causes a panic. Updating values of
+// existing keys is always allowed.
+//
+// Several goroutines may iterate over the same map at once, as long as none of
+// them modifies it.
 func (m *Map[K, V]) All() iter.Seq2[K, V] {
 	return func(yield func(K, V) bool) {
-		for e := m.kl.root.next; e != nil && e != &m.kl.root; {
-			next := e.next
-			if !yield(e.key, e.val) {
-				return
-			}
-			e = next
-		}
+		m.walk(m.kl.root.next, false, func(e *Element[K, V]) bool {
+			return yield(e.key, e.val)
+		})
 	}
 }
 
@@ -119,6 +131,7 @@ func (m *Map[K, V]) Len() int {
 
 // Keys returns a slice of all keys in the map, in the order they were inserted.
 func (m *Map[K, V]) Keys() []K {
+	m.kl.check()
 	keys := make([]K, 0, len(m.kv))
 	for e := m.kl.root.next; e != nil && e != &m.kl.root; e = e.next {
 		keys = append(keys, e.key)
@@ -128,6 +141,7 @@ func (m *Map[K, V]) Keys() []K {
 
 // Values returns a slice of all values in the map, in the order their keys were inserted.
 func (m *Map[K, V]) Values() []V {
+	m.kl.check()
 	values := make([]V, 0, len(m.kv))
 	for e := m.kl.root.next; e != nil && e != &m.kl.root; e = e.next {
 		values = append(values, e.val)
@@ -135,13 +149,40 @@ func (m *Map[K, V]) Values() []V {
 	return values
 }
 
-// Merge merges the key-value pairs from the target maps into the current map.
-func (m *Map[K, V]) Merge(target ...*Map[K, V]) {
-	for _, item := range target {
-		for k, v := range item.All() {
-			m.Set(k, v)
-		}
+// Front returns the first element of the map or nil if the map is empty.
+func (m *Map[K, V]) Front() *Element[K, V] {
+	if len(m.kv) == 0 {
+		return nil
+	}
+	return m.kl.root.next
+}
+
+// Back returns the last element of the map or nil if the map is empty.
+func (m *Map[K, V]) Back() *Element[K, V] {
+	if len(m.kv) == 0 {
+		return nil
+	}
+	return m.kl.root.prev
+}
+
+// GetElement returns the element associated with the given key or nil if the key does not exist.
+func (m *Map[K, V]) GetElement(key K) *Element[K, V] {
+	return m.kv[key]
+}
+
+// Remove removes the element from the map if it belongs to the map, and returns its value.
+func (m *Map[K, V]) Remove(e *Element[K, V]) V {
+	if m.kv[e.key] == e {
+		m.kl.delete(e)
+		delete(m.kv, e.key)
 	}
+	return e.val
+}
+
+// Merge merges the key-value pairs from the target maps into the current map.
+// Existing keys take the incoming value and keep their position.
+func (m *Map[K, V]) Merge(target ...OrderedMap[K, V]) {
+	m.MergeFunc(nil, MergeOptions{}, target...)
 }
 
 // Reverse reverses the order of elements in the map.
@@ -149,6 +190,7 @@ func (m *Map[K, V]) Reverse() {
 	if m.kv == nil || m.kl.root.next == &m.kl.root {
 		return
 	}
+	m.kl.check()
 
 	curr := m.kl.root.next
 	for curr != &m.kl.root {
@@ -156,4 +198,5 @@ func (m *Map[K, V]) Reverse() {
 		curr = curr.prev
 	}
 	m.kl.root.next, m.kl.root.prev = m.kl.root.prev, m.kl.root.next
+	m.kl.reordered()
 }
@@ -1,7 +1,9 @@
 package omap
 
 import (
+	"math/rand/v2"
 	"slices"
+	"sync"
 	"testing"
 )
 
@@ -258,3 +260,380 @@ func TestMap_Reverse(t *testing.T) {
 		t.Errorf("Values() after reverse = %v, want %v", m.Values(), wantVals)
 	}
 }
+
+func TestMap_Front_Back(t *testing.T) {
+	m := New[string, int]()
+	if m.Front() != nil || m.Back() != nil {
+		t.Error("Front()/Back() of empty map should be nil")
+	}
+
+	var zero Map[string, int]
+	if zero.Front() != nil || zero.Back() != nil {
+		t.Error("Front()/Back() of zero map should be nil")
+	}
+
+	m.Set("one", 1)
+	m.Set("two", 2)
+	m.Set("three", 3)
+
+	if e := m.Front(); e == nil || e.Key() != "one" {
+		t.Errorf("Front() = %v, want one", e)
+	}
+	if e := m.Back(); e == nil || e.Key() != "three" {
+		t.Errorf("Back() = %v, want three", e)
+	}
+
+	var keys []string
+	for e := m.Front(); e != nil; e = e.Next() {
+		keys = append(keys, e.Key())
+	}
+	if !slices.Equal(keys, []string{"one", "two", "three"}) {
+		t.Errorf("forward walk = %v, want [one two three]", keys)
+	}
+
+	keys = keys[:0]
+	for e := m.Back(); e != nil; e = e.Prev() {
+		keys = append(keys, e.Key())
+	}
+	if !slices.Equal(keys, []string{"three", "two", "one"}) {
+		t.Errorf("backward walk = %v, want [three two one]", keys)
+	}
+}
+
+func TestMap_GetElement(t *testing.T) {
+	m := New[string, int]()
+	m.Set("one", 1)
+	m.Set("two", 2)
+
+	e := m.GetElement("two")
+	if e == nil {
+		t.Fatal("GetElement(two) = nil")
+	}
+	if e.Prev().Key() != "one" {
+		t.Errorf("GetElement(two).Prev() = %v, want one", e.Prev().Key())
+	}
+
+	e.SetValue(20)
+	if val := m.Get("two"); val != 20 {
+		t.Errorf("Get(two) after SetValue = %v, want 20", val)
+	}
+
+	if m.GetElement("three") != nil {
+		t.Error("GetElement(three) should be nil")
+	}
+}
+
+func TestMap_Remove(t *testing.T) {
+	m := New[string, int]()
+	m.Set("one", 1)
+	m.Set("two", 2)
+	m.Set("three", 3)
+
+	if val := m.Remove(m.GetElement("two")); val != 2 {
+		t.Errorf("Remove(two) = %v, want 2", val)
+	}
+	if m.Has("two") {
+		t.Error("Has(two) after Remove = true, want false")
+	}
+	if !slices.Equal(m.Keys(), []string{"one", "three"}) {
+		t.Errorf("Keys() after Remove = %v, want [one three]", m.Keys())
+	}
+
+	// Removing an element of another map is a no-op
+	other := New[string, int]()
+	other.Set("one", 10)
+	other.Remove(m.GetElement("one"))
+	if other.Len() != 1 || m.Len() != 2 {
+		t.Errorf("Remove of foreign element changed maps: %d, %d", other.Len(), m.Len())
+	}
+}
+
+func TestMap_ConcurrentReaders(t *testing.T) {
+	// run with -race to check
+	m := New[int, int]()
+	for i := range 100 {
+		m.Set(i, i)
+	}
+	want := m.Keys()
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Go(func() {
+			var keys []int
+			for k := range m.All() {
+				keys = append(keys, k)
+			}
+			for range m.Backward() {
+			}
+			for range m.Between(10, 20) {
+			}
+			if !slices.Equal(keys, want) {
+				t.Errorf("All() = %v, want %v", keys, want)
+			}
+			if _, err := m.MarshalJSON(); err != nil {
+				t.Error(err)
+			}
+			if !m.Clone().Equal(m) {
+				t.Error("Clone() is not equal to the map")
+			}
+		})
+	}
+	wg.Wait()
+}
+
+func TestMap_All_Modify(t *testing.T) {
+	newMap := func() *Map[string, int] {
+		m := New[string, int]()
+		for i, k := range []string{"a", "b", "c", "d"} {
+			m.Set(k, i)
+		}
+		return m
+	}
+
+	tests := []struct {
+		name     string
+		body     func(m *Map[string, int], k string)
+		wantSeen []string
+		wantKeys []string
+	}{
+		{
+			name: "Delete current",
+			body: func(m *Map[string, int], k string) {
+				m.Delete(k)
+			},
+			wantSeen: []string{"a", "b", "c", "d"},
+			wantKeys: []string{},
+		},
+		{
+			name: "Delete next",
+			body: func(m *Map[string, int], k string) {
+				if k == "a" {
+					m.Delete("b")
+				}
+			},
+			wantSeen: []string{"a", "c", "d"},
+			wantKeys: []string{"a", "c", "d"},
+		},
+		{
+			name: "Delete run ahead",
+			body: func(m *Map[string, int], k string) {
+				if k == "a" {
+					m.Delete("b", "c")
+				}
+			},
+			wantSeen: []string{"a", "d"},
+			wantKeys: []string{"a", "d"},
+		},
+		{
+			name: "Move next behind",
+			body: func(m *Map[string, int], k string) {
+				if k == "b" {
+					m.MoveToFront("c")
+				}
+			},
+			wantSeen: []string{"a", "b", "d", "c"},
+			wantKeys: []string{"c", "a", "b", "d"},
+		},
+		{
+			name: "Move current ahead",
+			body: func(m *Map[string, int], k string) {
+				if k == "a" {
+					m.MoveToBack("a")
+				}
+			},
+			wantSeen: []string{"a", "b", "c", "d"},
+			wantKeys: []string{"b", "c", "d", "a"},
+		},
+		{
+			name: "Move every key to back",
+			body: func(m *Map[string, int], k string) {
+				m.MoveToBack(k)
+			},
+			wantSeen: []string{"a", "b", "c", "d"},
+			wantKeys: []string{"a", "b", "c", "d"},
+		},
+		{
+			name: "Move current into the middle",
+			body: func(m *Map[string, int], k string) {
+				if k == "a" {
+					m.MoveAfter("a", "c")
+				}
+			},
+			wantSeen: []string{"a", "b", "c", "d"},
+			wantKeys: []string{"b", "c", "a", "d"},
+		},
+		{
+			name: "Move next ahead",
+			body: func(m *Map[string, int], k string) {
+				if k == "a" {
+					m.MoveToBack("b")
+				}
+			},
+			wantSeen: []string{"a", "c", "d", "b"},
+			wantKeys: []string{"a", "c", "d", "b"},
+		},
+		{
+			name: "Delete current and move next",
+			body: func(m *Map[string, int], k string) {
+				if k == "b" {
+					m.Delete("b")
+					m.MoveToFront("c")
+				}
+			},
+			wantSeen: []string{"a", "b", "c", "d"},
+			wantKeys: []string{"c", "a", "d"},
+		},
+		{
+			name: "Swap current and next",
+			body: func(m *Map[string, int], k string) {
+				if k == "b" {
+					m.Swap("b", "c")
+				}
+			},
+			wantSeen: []string{"a", "b", "c", "d"},
+			wantKeys: []string{"a", "c", "b", "d"},
+		},
+		{
+			name: "Update values",
+			body: func(m *Map[string, int], k string) {
+				m.Set(k, 10)
+				m.Set("d", 10)
+			},
+			wantSeen: []string{"a", "b", "c", "d"},
+			wantKeys: []string{"a", "b", "c", "d"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMap()
+			seen := []string{}
+			for k := range m.All() {
+				seen = append(seen, k)
+				tt.body(m, k)
+			}
+			if !slices.Equal(seen, tt.wantSeen) {
+				t.Errorf("seen = %v, want %v", seen, tt.wantSeen)
+			}
+			if !slices.Equal(m.Keys(), tt.wantKeys) {
+				t.Errorf("Keys() = %v, want %v", m.Keys(), tt.wantKeys)
+			}
+		})
+	}
+
+	t.Run("Backward delete next", func(t *testing.T) {
+		m := newMap()
+		var seen []string
+		for k := range m.Backward() {
+			seen = append(seen, k)
+			if k == "d" {
+				m.Delete("c")
+			}
+		}
+		if want := []string{"d", "b", "a"}; !slices.Equal(seen, want) {
+			t.Errorf("seen = %v, want %v", seen, want)
+		}
+	})
+
+	t.Run("Nested", func(t *testing.T) {
+		m := newMap()
+		var seen []string
+		for k := range m.All() {
+			for k2 := range m.All() {
+				if k2 != k {
+					m.Delete(k2)
+				}
+			}
+			seen = append(seen, k)
+		}
+		if want := []string{"a"}; !slices.Equal(seen, want) {
+			t.Errorf("seen = %v, want %v", seen, want)
+		}
+	})
+
+	t.Run("Move every key at once", func(t *testing.T) {
+		m := New[int, int]()
+		for i := range 20 {
+			m.Set(i, i)
+		}
+		var seen []int
+		for k := range m.All() {
+			seen = append(seen, k)
+			if k == 10 {
+				for _, k := range m.Keys() {
+					m.MoveToBack(k)
+				}
+			}
+		}
+		slices.Sort(seen)
+		if want := m.Keys(); !slices.Equal(seen, want) {
+			t.Errorf("seen = %v, want every key once", seen)
+		}
+	})
+
+	t.Run("Random", func(t *testing.T) {
+		// each entry is produced at most once, and entries that are not deleted
+		// are always produced
+		r := rand.New(rand.NewPCG(5, 6))
+		for range 200 {
+			m := New[int, int]()
+			for i := range 20 {
+				m.Set(i, i)
+			}
+			seen := make(map[int]bool)
+			it := m.All()
+			if r.IntN(2) == 0 {
+				it = m.Backward()
+			}
+			for k := range it {
+				if seen[k] {
+					t.Fatalf("key %d produced twice", k)
+				}
+				seen[k] = true
+				for range r.IntN(3) {
+					other := r.IntN(20)
+					if !m.Has(other) {
+						continue
+					}
+					switch r.IntN(4) {
+					case 0:
+						m.Delete(other)
+					case 1:
+						m.MoveToFront(other)
+					case 2:
+						m.MoveToBack(other)
+					default:
+						m.MoveAfter(other, m.Keys()[r.IntN(m.Len())])
+					}
+				}
+			}
+			for k := range m.All() {
+				if !seen[k] {
+					t.Fatalf("key %d was not produced", k)
+				}
+			}
+		}
+	})
+
+	panics := []struct {
+		name string
+		body func(m *Map[string, int])
+	}{
+		{"Insert", func(m *Map[string, int]) { m.Set("e", 4) }},
+		{"Clear", func(m *Map[string, int]) { m.Clear() }},
+		{"Reverse", func(m *Map[string, int]) { m.Reverse() }},
+	}
+	for _, tt := range panics {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newMap()
+			defer func() {
+				if r := recover(); r != "omap: map modified during iteration" {
+					t.Errorf("recover() = %v, want modification panic", r)
+				}
+			}()
+			for range m.All() {
+				tt.body(m)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package omap
+
+// MergePosition determines where merged keys are placed in the target map.
+type MergePosition int
+
+const (
+	// PositionKeep keeps existing keys at their position and appends new keys to the end.
+	PositionKeep MergePosition = iota
+	// PositionMoveToEnd moves every merged key to the end, in the order of the incoming map.
+	PositionMoveToEnd
+	// PositionIncoming places each merged key right after the previously merged key,
+	// so that the keys follow the order of the incoming map. The first merged key
+	// keeps its position, or is appended to the end if it is new.
+	PositionIncoming
+)
+
+// MergeOptions configures MergeFunc.
+type MergeOptions struct {
+	// Position determines where merged keys are placed.
+	Position MergePosition
+	// SkipExisting leaves existing keys untouched, like TrySet.
+	SkipExisting bool
+}
+
+// MergeFunc merges the key-value pairs from the target maps into the current map.
+// For keys that already exist, resolve is called with the current and the incoming
+// value, and its result is stored. A nil resolve takes the incoming value.
+func (m *Map[K, V]) MergeFunc(resolve func(key K, old, new V) V, opts MergeOptions, target ...OrderedMap[K, V]) {
+	m.lazyInit()
+	for _, item := range target {
+		m.mergeFrom(item, resolve, opts)
+	}
+}
+
+func (m *Map[K, V]) mergeFrom(src OrderedMap[K, V], resolve func(key K, old, new V) V, opts MergeOptions) {
+	// bound the walk by the initial length, since merging a map into itself
+	// may move entries ahead of the iteration
+	n := src.Len()
+	var prev *Element[K, V]
+	for k, v := range src.All() {
+		if n--; n < 0 {
+			break
+		}
+		e, exists := m.kv[k]
+		switch {
+		case !exists:
+			e = m.kl.append(k, v)
+			m.kv[k] = e
+			if opts.Position == PositionIncoming && prev != nil {
+				m.kl.move(e, prev)
+			}
+		case opts.SkipExisting:
+		default:
+			if resolve != nil {
+				v = resolve(k, e.val, v)
+			}
+			e.val = v
+			switch opts.Position {
+			case PositionMoveToEnd:
+				m.kl.move(e, m.kl.root.prev)
+			case PositionIncoming:
+				if prev != nil {
+					m.kl.move(e, prev)
+				}
+			}
+		}
+		prev = e
+	}
+}
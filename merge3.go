@@ -0,0 +1,165 @@
+package omap
+
+import "fmt"
+
+// ConflictKind is the kind of a Conflict.
+type ConflictKind int
+
+const (
+	// ConflictModify means both sides changed the value of a key differently.
+	ConflictModify ConflictKind = iota
+	// ConflictAdd means both sides added a key with different values.
+	ConflictAdd
+	// ConflictDelete means one side deleted a key that the other side changed.
+	ConflictDelete
+)
+
+// String returns the name of the kind.
+func (k ConflictKind) String() string {
+	switch k {
+	case ConflictModify:
+		return "modify/modify"
+	case ConflictAdd:
+		return "add/add"
+	case ConflictDelete:
+		return "modify/delete"
+	}
+	return "ConflictKind(" + fmt.Sprint(int(k)) + ")"
+}
+
+// Conflict describes a key that ours and theirs changed in incompatible ways.
+// The In fields report whether the key exists in the respective map.
+type Conflict[K comparable, V any] struct {
+	Kind                     ConflictKind
+	Key                      K
+	Base, Ours, Theirs       V
+	InBase, InOurs, InTheirs bool
+}
+
+// version is the state of a key in one of the maps of a three-way merge.
+type version[V any] struct {
+	val V
+	ok  bool
+}
+
+func (v version[V]) equal(other version[V]) bool {
+	return v.ok == other.ok && (!v.ok || valueEqual(v.val, other.val))
+}
+
+func lookup[K comparable, V any](m *Map[K, V], key K) version[V] {
+	v, ok := m.TryGet(key)
+	return version[V]{v, ok}
+}
+
+// Merge3 merges the changes that ours and theirs made to base, like a three-way merge
+// in version control, and returns the result along with the conflicts it found.
+// A nil map is treated as empty.
+//
+// A key takes the version of the side that changed it. If both sides changed it in
+// different ways, the conflict is reported and resolve decides the value, or whether
+// to drop the key by returning false. A nil resolve keeps the version of ours.
+//
+// The result follows the order of ours. Keys that theirs added or moved, compared to
+// base, are then placed after the key that precedes them in theirs, unless ours moved
+// them as well. Keys that theirs appended after the last key of base go to the end.
+func Merge3[K comparable, V any](base, ours, theirs *Map[K, V], resolve func(c Conflict[K, V]) (V, bool)) (*Map[K, V], []Conflict[K, V]) {
+	base, ours, theirs = orEmpty(base), orEmpty(ours), orEmpty(theirs)
+	m := ours.Clone()
+	m.lazyInit()
+
+	var conflicts []Conflict[K, V]
+	added := make(map[K]V)
+	merge := func(k K) {
+		b, o, t := lookup(base, k), lookup(ours, k), lookup(theirs, k)
+		v := o
+		switch {
+		case o.equal(t), t.equal(b):
+		case o.equal(b):
+			v = t
+		default:
+			c := Conflict[K, V]{
+				Kind: ConflictModify, Key: k,
+				Base: b.val, Ours: o.val, Theirs: t.val,
+				InBase: b.ok, InOurs: o.ok, InTheirs: t.ok,
+			}
+			switch {
+			case !o.ok || !t.ok:
+				c.Kind = ConflictDelete
+			case !b.ok:
+				c.Kind = ConflictAdd
+			}
+			conflicts = append(conflicts, c)
+			if resolve != nil {
+				v.val, v.ok = resolve(c)
+			}
+		}
+
+		switch e, exists := m.kv[k]; {
+		case !v.ok:
+			m.Delete(k)
+		case exists:
+			e.val = v.val
+		default:
+			added[k] = v.val
+		}
+	}
+	for k := range ours.KeysSeq() {
+		merge(k)
+	}
+	for k := range theirs.KeysSeq() {
+		if !ours.Has(k) {
+			merge(k)
+		}
+	}
+	for k := range base.KeysSeq() {
+		if !ours.Has(k) && !theirs.Has(k) {
+			merge(k)
+		}
+	}
+
+	// replay the positional changes of theirs
+	movedOurs, movedTheirs := moved(Diff(base, ours)), moved(Diff(base, theirs))
+	place := func(k K) bool {
+		return movedTheirs[k] && !movedOurs[k]
+	}
+	// keys that theirs appended after the last key of base go to the end, even if
+	// ours moved that key
+	keys := theirs.Keys()
+	tail := len(keys)
+	for ; tail > 0; tail-- {
+		if k := keys[tail-1]; m.Has(k) && !place(k) {
+			break
+		}
+	}
+	if last := base.Back(); tail == 0 || last == nil || last.key != keys[tail-1] {
+		tail = -1
+	}
+	prev := &m.kl.root
+	for i, k := range keys {
+		if i == tail {
+			prev = m.kl.root.prev
+		}
+		e, exists := m.kv[k]
+		if v, ok := added[k]; ok {
+			e = m.kl.insert(&Element[K, V]{key: k, val: v}, prev)
+			m.kv[k] = e
+		} else if exists && place(k) {
+			m.kl.move(e, prev)
+		}
+		if e != nil {
+			prev = e
+		}
+	}
+	return m, conflicts
+}
+
+// moved returns the keys that p inserts or moves.
+func moved[K comparable, V any](p Patch[K, V]) map[K]bool {
+	keys := make(map[K]bool)
+	for _, op := range p {
+		if op.Kind == OpInsert || op.Kind == OpMove {
+			keys[op.Key] = true
+		}
+	}
+	return keys
+}
@@ -0,0 +1,111 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMerge3(t *testing.T) {
+	base := mapOf[int]("a", 1, "b", 2, "c", 3, "d", 4)
+	ours := mapOf[int]("a", 10, "b", 2, "c", 3, "d", 4, "x", 5) // change a, add x
+	theirs := mapOf[int]("c", 3, "a", 1, "b", 20, "y", 6)       // move c, change b, delete d, add y
+
+	m, conflicts := Merge3(base, ours, theirs, nil)
+	if len(conflicts) != 0 {
+		t.Errorf("Merge3() conflicts = %v", conflicts)
+	}
+	want := mapOf[int]("c", 3, "a", 10, "b", 20, "y", 6, "x", 5)
+	if !m.Equal(want) {
+		t.Errorf("Merge3() = %v %v, want %v %v", m.Keys(), m.Values(), want.Keys(), want.Values())
+	}
+	if m == ours {
+		t.Error("Merge3() returned ours")
+	}
+	if !ours.Equal(mapOf[int]("a", 10, "b", 2, "c", 3, "d", 4, "x", 5)) {
+		t.Error("Merge3() modified ours")
+	}
+}
+
+func TestMerge3_Order(t *testing.T) {
+	base := mapOf[int]("a", 1, "b", 2, "c", 3, "d", 4)
+	ours := mapOf[int]("b", 2, "a", 1, "c", 3, "d", 4)   // swap a and b
+	theirs := mapOf[int]("a", 1, "b", 2, "d", 4, "c", 3) // swap c and d
+
+	m, _ := Merge3(base, ours, theirs, nil)
+	if want := []string{"b", "a", "d", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Merge3() keys = %v, want %v", m.Keys(), want)
+	}
+
+	// both sides move the same key: ours wins
+	ours = mapOf[int]("b", 2, "c", 3, "d", 4, "a", 1)
+	theirs = mapOf[int]("b", 2, "a", 1, "c", 3, "d", 4)
+	m, _ = Merge3(base, ours, theirs, nil)
+	if want := []string{"b", "c", "d", "a"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Merge3() keys = %v, want %v", m.Keys(), want)
+	}
+
+	// inserts from theirs follow their predecessor in theirs
+	ours = mapOf[int]("a", 1, "b", 2, "c", 3, "d", 4)
+	theirs = mapOf[int]("y", 0, "a", 1, "b", 2, "x", 0, "c", 3, "d", 4)
+	m, _ = Merge3(base, ours, theirs, nil)
+	if want := []string{"y", "a", "b", "x", "c", "d"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Merge3() keys = %v, want %v", m.Keys(), want)
+	}
+
+	// keys appended by theirs stay at the end, even if ours moved the last key of base
+	base = mapOf[int]("a", 1, "b", 2, "c", 3)
+	ours = mapOf[int]("c", 3, "a", 1, "b", 2)
+	theirs = mapOf[int]("a", 1, "b", 2, "c", 3, "d", 4, "e", 5)
+	m, _ = Merge3(base, ours, theirs, nil)
+	if want := []string{"c", "a", "b", "d", "e"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Merge3() keys = %v, want %v", m.Keys(), want)
+	}
+}
+
+func TestMerge3_Conflicts(t *testing.T) {
+	base := mapOf[int]("a", 1, "b", 2, "c", 3)
+	ours := mapOf[int]("a", 10, "b", 20, "x", 1)   // change a and b, delete c, add x
+	theirs := mapOf[int]("a", 11, "c", 30, "x", 2) // change a and c, delete b, add x
+
+	m, conflicts := Merge3(base, ours, theirs, nil)
+	want := []Conflict[string, int]{
+		{Kind: ConflictModify, Key: "a", Base: 1, Ours: 10, Theirs: 11, InBase: true, InOurs: true, InTheirs: true},
+		{Kind: ConflictDelete, Key: "b", Base: 2, Ours: 20, InBase: true, InOurs: true},
+		{Kind: ConflictAdd, Key: "x", Ours: 1, Theirs: 2, InOurs: true, InTheirs: true},
+		{Kind: ConflictDelete, Key: "c", Base: 3, Theirs: 30, InBase: true, InTheirs: true},
+	}
+	if !slices.Equal(conflicts, want) {
+		t.Errorf("Merge3() conflicts =\n%v\nwant\n%v", conflicts, want)
+	}
+	if !m.Equal(ours) {
+		t.Errorf("Merge3() without resolver = %v, want ours", m.Keys())
+	}
+
+	m, conflicts = Merge3(base, ours, theirs, func(c Conflict[string, int]) (int, bool) {
+		if c.Kind == ConflictDelete {
+			return c.Base, true
+		}
+		return c.Ours + c.Theirs, true
+	})
+	if len(conflicts) != 4 {
+		t.Errorf("Merge3() with resolver reported %d conflicts, want 4", len(conflicts))
+	}
+	// the deleted c comes back after its predecessor in theirs
+	if want := mapOf[int]("a", 21, "c", 3, "b", 2, "x", 3); !m.Equal(want) {
+		t.Errorf("Merge3() with resolver = %v %v, want %v %v", m.Keys(), m.Values(), want.Keys(), want.Values())
+	}
+}
+
+func TestMerge3_Nil(t *testing.T) {
+	ours := mapOf[int]("a", 1)
+	theirs := mapOf[int]("b", 2)
+	m, conflicts := Merge3(nil, ours, theirs, nil)
+	if len(conflicts) != 0 || !slices.Equal(m.Keys(), []string{"b", "a"}) {
+		t.Errorf("Merge3(nil, ...) = %v, %v", m.Keys(), conflicts)
+	}
+
+	m, _ = Merge3(ours, nil, nil, nil)
+	if m.Len() != 0 {
+		t.Errorf("Merge3(base, nil, nil).Len() = %d, want 0", m.Len())
+	}
+}
@@ -0,0 +1,112 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMap_MergeFunc(t *testing.T) {
+	newBase := func() *Map[string, int] {
+		m := New[string, int]()
+		for i, k := range []string{"a", "b", "c", "d"} {
+			m.Set(k, i+1)
+		}
+		return m
+	}
+	newOverride := func(keys ...string) *Map[string, int] {
+		m := New[string, int]()
+		for _, k := range keys {
+			m.Set(k, 10)
+		}
+		return m
+	}
+	sum := func(key string, old, new int) int {
+		return old + new
+	}
+
+	tests := []struct {
+		name     string
+		resolve  func(key string, old, new int) int
+		opts     MergeOptions
+		incoming []string
+		wantKeys []string
+		wantVals []int
+	}{
+		{
+			name:     "Keep position",
+			incoming: []string{"x", "c", "a"},
+			wantKeys: []string{"a", "b", "c", "d", "x"},
+			wantVals: []int{10, 2, 10, 4, 10},
+		},
+		{
+			name:     "Resolve",
+			resolve:  sum,
+			incoming: []string{"c", "x"},
+			wantKeys: []string{"a", "b", "c", "d", "x"},
+			wantVals: []int{1, 2, 13, 4, 10},
+		},
+		{
+			name:     "Move to end",
+			opts:     MergeOptions{Position: PositionMoveToEnd},
+			incoming: []string{"c", "x", "a"},
+			wantKeys: []string{"b", "d", "c", "x", "a"},
+			wantVals: []int{2, 4, 10, 10, 10},
+		},
+		{
+			name:     "Incoming order",
+			opts:     MergeOptions{Position: PositionIncoming},
+			incoming: []string{"c", "a", "x", "b"},
+			wantKeys: []string{"c", "a", "x", "b", "d"},
+			wantVals: []int{10, 10, 10, 10, 4},
+		},
+		{
+			name:     "Incoming order with new first key",
+			opts:     MergeOptions{Position: PositionIncoming},
+			incoming: []string{"x", "b", "a"},
+			wantKeys: []string{"c", "d", "x", "b", "a"},
+			wantVals: []int{3, 4, 10, 10, 10},
+		},
+		{
+			name:     "Skip existing",
+			resolve:  sum,
+			opts:     MergeOptions{SkipExisting: true},
+			incoming: []string{"c", "x", "a"},
+			wantKeys: []string{"a", "b", "c", "d", "x"},
+			wantVals: []int{1, 2, 3, 4, 10},
+		},
+		{
+			name:     "Skip existing with incoming order",
+			opts:     MergeOptions{Position: PositionIncoming, SkipExisting: true},
+			incoming: []string{"b", "x"},
+			wantKeys: []string{"a", "b", "x", "c", "d"},
+			wantVals: []int{1, 2, 10, 3, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newBase()
+			m.MergeFunc(tt.resolve, tt.opts, newOverride(tt.incoming...))
+			if !slices.Equal(m.Keys(), tt.wantKeys) {
+				t.Errorf("Keys() = %v, want %v", m.Keys(), tt.wantKeys)
+			}
+			if !slices.Equal(m.Values(), tt.wantVals) {
+				t.Errorf("Values() = %v, want %v", m.Values(), tt.wantVals)
+			}
+		})
+	}
+}
+
+func TestMap_MergeFunc_Layers(t *testing.T) {
+	var m Map[string, int]
+	m.MergeFunc(nil, MergeOptions{Position: PositionIncoming}, newABC(), newABC())
+	if want := []string{"a", "b", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+
+	// Merging a map into itself is allowed
+	m.MergeFunc(func(_ string, old, new int) int { return old + new }, MergeOptions{Position: PositionMoveToEnd}, &m)
+	if want := []int{2, 4, 6}; !slices.Equal(m.Values(), want) {
+		t.Errorf("Values() = %v, want %v", m.Values(), want)
+	}
+}
@@ -0,0 +1,27 @@
+package omap
+
+import "iter"
+
+// OrderedMap is the common interface of the ordered maps in this package.
+// It allows writing code that works with any of them, such as Map and SortedMap.
+type OrderedMap[K comparable, V any] interface {
+	// Set adds a key-value pair, or updates the value of an existing key.
+	Set(key K, value V)
+	// TryGet returns the value of the key and whether the key exists.
+	TryGet(key K) (V, bool)
+	// Delete removes the keys.
+	Delete(keys ...K)
+	// Len returns the number of entries.
+	Len() int
+	// All returns an iterator over the entries in the order of the map.
+	All() iter.Seq2[K, V]
+}
+
+var (
+	_ OrderedMap[string, int] = (*Map[string, int])(nil)
+	_ OrderedMap[string, int] = (*SortedMap[string, int])(nil)
+	_ OrderedMap[string, int] = (*SyncMap[string, int])(nil)
+	_ OrderedMap[string, int] = (*COWMap[string, int])(nil)
+	_ OrderedMap[string, int] = (*ShardedMap[string, int])(nil)
+	_ OrderedMap[string, int] = (*Cache[string, int])(nil)
+)
@@ -0,0 +1,151 @@
+package omap
+
+import (
+	"cmp"
+	"encoding/json"
+	"iter"
+	"slices"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// pairs is a minimal OrderedMap backed by a slice.
+type pairs []Entry[string, int]
+
+func (p *pairs) Set(key string, value int) {
+	for i := range *p {
+		if (*p)[i].Key == key {
+			(*p)[i].Value = value
+			return
+		}
+	}
+	*p = append(*p, Entry[string, int]{key, value})
+}
+
+func (p *pairs) TryGet(key string) (int, bool) {
+	for _, e := range *p {
+		if e.Key == key {
+			return e.Value, true
+		}
+	}
+	return 0, false
+}
+
+func (p *pairs) Delete(keys ...string) {
+	*p = slices.DeleteFunc(*p, func(e Entry[string, int]) bool {
+		return slices.Contains(keys, e.Key)
+	})
+}
+
+func (p *pairs) Len() int {
+	return len(*p)
+}
+
+func (p *pairs) All() iter.Seq2[string, int] {
+	return func(yield func(string, int) bool) {
+		for _, e := range *p {
+			if !yield(e.Key, e.Value) {
+				return
+			}
+		}
+	}
+}
+
+func (p *pairs) MarshalJSON() ([]byte, error) {
+	return MarshalJSON(p)
+}
+
+func (p *pairs) UnmarshalJSON(data []byte) error {
+	return UnmarshalJSON(data, p)
+}
+
+func (p *pairs) MarshalYAML() (any, error) {
+	return MarshalYAML(p)
+}
+
+func (p *pairs) UnmarshalYAML(n *yaml.Node) error {
+	return UnmarshalYAML(n, p)
+}
+
+func TestOrderedMap_Sort(t *testing.T) {
+	for _, m := range []OrderedMap[string, int]{New[string, int](), &pairs{}} {
+		m.Set("b", 1)
+		m.Set("c", 2)
+		m.Set("a", 1)
+		keys := func() []string {
+			return collectKeys(m.All())
+		}
+
+		SortByValue(m)
+		if want := []string{"b", "a", "c"}; !slices.Equal(keys(), want) {
+			t.Errorf("%T: SortByValue() keys = %v, want %v", m, keys(), want)
+		}
+
+		SortDesc(m)
+		if want := []string{"c", "b", "a"}; !slices.Equal(keys(), want) {
+			t.Errorf("%T: SortDesc() keys = %v, want %v", m, keys(), want)
+		}
+		SortParallel(m, func(k1, k2 string) int { return len(k1) - len(k2) })
+		if want := []string{"c", "b", "a"}; !slices.Equal(keys(), want) {
+			t.Errorf("%T: SortParallel() keys = %v, want %v", m, keys(), want)
+		}
+	}
+
+	Sort[string, int](nil) // Should not panic
+
+	// a SortedMap keeps its own order, so sorting it is a mistake
+	for name, sort := range map[string]func(m OrderedMap[string, int]){
+		"SortByValue":  SortByValue[string, int],
+		"SortParallel": func(m OrderedMap[string, int]) { SortParallel(m, cmp.Compare) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s() on a SortedMap did not panic", name)
+				}
+			}()
+			sort(NewSorted[string, int]())
+		}()
+	}
+}
+
+func TestOrderedMap_Merge(t *testing.T) {
+	s := NewSorted[string, int]()
+	s.Set("b", 2)
+	s.Set("a", 1)
+	p := &pairs{{"z", 26}, {"a", 0}}
+
+	m := New[string, int]()
+	m.Set("a", -1)
+	m.Merge(s, p)
+	if want := mapOf[int]("a", 0, "b", 2, "z", 26); !m.Equal(want) {
+		t.Errorf("Merge() = %v %v, want %v %v", m.Keys(), m.Values(), want.Keys(), want.Values())
+	}
+}
+
+func TestOrderedMap_Encoding(t *testing.T) {
+	p := &pairs{}
+	if err := json.Unmarshal([]byte(`{"b":2,"a":1}`), p); err != nil {
+		t.Fatalf("json.Unmarshal() = %v", err)
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		t.Fatalf("json.Marshal() = %v", err)
+	}
+	if want := `{"b":2,"a":1}`; string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+
+	p = &pairs{}
+	if err := yaml.Unmarshal([]byte("b: 2\na: 1\n"), p); err != nil {
+		t.Fatalf("yaml.Unmarshal() = %v", err)
+	}
+	b, err = yaml.Marshal(p)
+	if err != nil {
+		t.Fatalf("yaml.Marshal() = %v", err)
+	}
+	if want := "b: 2\na: 1\n"; string(b) != want {
+		t.Errorf("yaml.Marshal() = %q, want %q", b, want)
+	}
+}
@@ -0,0 +1,181 @@
+package omap
+
+import "math"
+
+// Policy decides which entry a Cache evicts instead of the least recently used one.
+// The cache reports every key it stores, accesses and removes, and asks for victims
+// whenever it needs room.
+//
+// Implementations need not be safe for concurrent use, since a Cache is not.
+type Policy[K comparable] interface {
+	// Insert records a new key.
+	Insert(key K)
+	// Access records an access to a key, by a lookup or by an update of its value.
+	Access(key K)
+	// Remove forgets a key that was evicted or deleted.
+	Remove(key K)
+	// Victim returns the key to evict next, or false if no keys are recorded.
+	// The key stays recorded until Remove is called.
+	Victim() (K, bool)
+}
+
+// NewFIFOPolicy returns a Policy that evicts the oldest key, regardless of accesses.
+func NewFIFOPolicy[K comparable]() Policy[K] {
+	return &fifoPolicy[K]{}
+}
+
+// NewLFUPolicy returns a Policy that evicts the least frequently used key. Among keys
+// with the same number of accesses, the one that reached that number first goes first.
+func NewLFUPolicy[K comparable]() Policy[K] {
+	return &lfuPolicy[K]{}
+}
+
+// New2QPolicy returns a Policy after the 2Q algorithm, which resists scans better than
+// LRU. New keys enter a FIFO queue, which is kept at about a quarter of the keys.
+// Keys evicted from it are remembered for a while, and move to an LRU queue of hot
+// keys if they are inserted again. Deleted keys are remembered in the same way.
+func New2QPolicy[K comparable]() Policy[K] {
+	return &twoQueuePolicy[K]{}
+}
+
+// fifoPolicy evicts the keys in the order of a Map.
+type fifoPolicy[K comparable] struct {
+	keys Map[K, struct{}]
+}
+
+func (p *fifoPolicy[K]) Insert(key K) {
+	p.keys.Set(key, struct{}{})
+}
+
+func (p *fifoPolicy[K]) Access(key K) {}
+
+func (p *fifoPolicy[K]) Remove(key K) {
+	p.keys.Delete(key)
+}
+
+func (p *fifoPolicy[K]) Victim() (K, bool) {
+	return front(&p.keys)
+}
+
+// front returns the first key of the map.
+func front[K comparable](m *Map[K, struct{}]) (K, bool) {
+	if e := m.Front(); e != nil {
+		return e.key, true
+	}
+	var zero K
+	return zero, false
+}
+
+// lfuPolicy keeps the keys in buckets by access count. Each bucket is in the order in
+// which its keys reached the count. Accesses and evictions take O(1), except that a
+// Remove that empties the smallest bucket makes the next Victim scan the buckets.
+type lfuPolicy[K comparable] struct {
+	counts  map[K]int
+	buckets map[int]*Map[K, struct{}]
+	min     int // the smallest count, if its bucket exists
+}
+
+// add adds the key to the bucket of the count.
+func (p *lfuPolicy[K]) add(key K, count int) {
+	b := p.buckets[count]
+	if b == nil {
+		b = New[K, struct{}]()
+		p.buckets[count] = b
+	}
+	b.Set(key, struct{}{})
+	p.counts[key] = count
+}
+
+// unlink removes the key from the bucket of the count, and drops the bucket if it is empty.
+func (p *lfuPolicy[K]) unlink(key K, count int) {
+	b := p.buckets[count]
+	if b.Delete(key); b.Len() == 0 {
+		delete(p.buckets, count)
+	}
+}
+
+func (p *lfuPolicy[K]) Insert(key K) {
+	if p.counts == nil {
+		p.counts = make(map[K]int)
+		p.buckets = make(map[int]*Map[K, struct{}])
+	}
+	p.add(key, 1)
+	p.min = 1
+}
+
+func (p *lfuPolicy[K]) Access(key K) {
+	count, ok := p.counts[key]
+	if !ok || count == math.MaxInt {
+		return
+	}
+	p.unlink(key, count)
+	p.add(key, count+1)
+	if p.min == count && p.buckets[count] == nil {
+		p.min = count + 1
+	}
+}
+
+func (p *lfuPolicy[K]) Remove(key K) {
+	if count, ok := p.counts[key]; ok {
+		delete(p.counts, key)
+		p.unlink(key, count)
+	}
+}
+
+func (p *lfuPolicy[K]) Victim() (K, bool) {
+	if len(p.counts) == 0 {
+		var zero K
+		return zero, false
+	}
+	if p.buckets[p.min] == nil {
+		// the bucket was emptied by Remove; find the next one
+		p.min = math.MaxInt
+		for count := range p.buckets {
+			p.min = min(p.min, count)
+		}
+	}
+	return front(p.buckets[p.min])
+}
+
+// twoQueuePolicy implements 2Q with its three queues: in holds new keys in FIFO order,
+// hot holds keys that came back in LRU order, and out holds the ghosts of keys that
+// were removed from in, in FIFO order.
+type twoQueuePolicy[K comparable] struct {
+	in, hot, out Map[K, struct{}]
+}
+
+func (p *twoQueuePolicy[K]) Insert(key K) {
+	if p.out.Has(key) {
+		p.out.Delete(key)
+		p.hot.Set(key, struct{}{})
+	} else {
+		p.in.Set(key, struct{}{})
+	}
+
+	// forget the oldest ghosts, keeping up to half as many as keys; this happens here
+	// rather than in Remove, so that the evictions that make room for a key cannot
+	// push out its own ghost
+	for limit := max(1, (p.in.Len()+p.hot.Len())/2); p.out.Len() > limit; {
+		p.out.Remove(p.out.Front())
+	}
+}
+
+func (p *twoQueuePolicy[K]) Access(key K) {
+	p.hot.MoveToBack(key) // accesses to keys in in do not count, like in 2Q
+}
+
+func (p *twoQueuePolicy[K]) Remove(key K) {
+	if p.in.Has(key) {
+		p.in.Delete(key)
+		p.out.Set(key, struct{}{})
+	} else {
+		p.hot.Delete(key)
+	}
+}
+
+func (p *twoQueuePolicy[K]) Victim() (K, bool) {
+	if n := p.in.Len(); n > 0 && (p.hot.Len() == 0 || 4*n > n+p.hot.Len()) {
+		return front(&p.in)
+	}
+	return front(&p.hot)
+}
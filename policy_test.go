@@ -0,0 +1,135 @@
+package omap
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+// evictions runs the accesses against a cache of the given capacity and returns the evicted keys.
+// A negative access deletes the key.
+func evictions(policy Policy[int], capacity int, accesses ...int) []int {
+	var evicted []int
+	c := NewLRU(capacity, &CacheOptions[int, int]{
+		Policy:  policy,
+		OnEvict: func(k, v int) { evicted = append(evicted, k) },
+	})
+	for _, k := range accesses {
+		switch {
+		case k < 0:
+			c.Delete(-k)
+		case c.Has(k):
+			c.Get(k)
+		default:
+			c.Set(k, k)
+		}
+	}
+	return evicted
+}
+
+func TestPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy[int]
+		capacity int
+		accesses []int
+		want     []int
+	}{
+		{"LRU", nil, 2, []int{1, 2, 1, 3, 4}, []int{2, 1}},
+		{"FIFO", NewFIFOPolicy[int](), 2, []int{1, 2, 1, 3, 4}, []int{1, 2}},
+		{"LFU", NewLFUPolicy[int](), 2, []int{1, 1, 2, 3, 3, 3, 4}, []int{2, 1}},
+		{"LFU ties", NewLFUPolicy[int](), 3, []int{1, 2, 3, 3, 2, 4, 5}, []int{1, 4}},
+		// hits in the FIFO queue do not protect 5
+		{"2Q", New2QPolicy[int](), 4, []int{1, 2, 3, 4, 5, 5, 6, 7, 8, 9}, []int{1, 2, 3, 4, 5}},
+		// 1 is evicted from the FIFO queue, and becomes hot when it comes back
+		{"2Q ghost", New2QPolicy[int](), 4, []int{1, 2, 3, 4, 5, 1, 6, 7, 8}, []int{1, 2, 3, 4, 5}},
+	}
+	for _, tt := range tests {
+		if got := evictions(tt.policy, tt.capacity, tt.accesses...); !slices.Equal(got, tt.want) {
+			t.Errorf("%s: evicted %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestLFUPolicy_Remove(t *testing.T) {
+	p := NewLFUPolicy[int]()
+	p.Insert(1)
+	p.Insert(2)
+	p.Insert(3)
+	p.Access(2)
+	p.Access(2)
+	p.Access(3)
+	p.Remove(1) // empties the bucket of the smallest count
+	if k, ok := p.Victim(); !ok || k != 3 {
+		t.Errorf("Victim() = %d, %v, want 3", k, ok)
+	}
+}
+
+func Test2QPolicy_ScanResistance(t *testing.T) {
+	// a hot key survives a scan through many keys that are used once
+	p := New2QPolicy[int]()
+	c := NewLRU(8, &CacheOptions[int, int]{Policy: p})
+	c.Set(0, 0)
+	c.Set(1, 1)
+	for i := 2; i < 12; i++ {
+		c.Set(i, i)
+	}
+	c.Set(0, 0) // 0 is remembered, so it becomes hot
+	for i := 100; i < 200; i++ {
+		c.Set(i, i)
+	}
+	if !c.Has(0) {
+		t.Errorf("2Q evicted the hot key during a scan: %v", c.Keys())
+	}
+
+	lru := NewLRU[int, int](8, nil)
+	lru.Set(0, 0)
+	for i := 100; i < 200; i++ {
+		lru.Set(i, i)
+	}
+	if lru.Has(0) {
+		t.Error("LRU kept the key during a scan")
+	}
+}
+
+func TestPolicies_Random(t *testing.T) {
+	// whatever the policy, the cache never exceeds its capacity and its victims are exactly its keys
+	r := rand.New(rand.NewPCG(3, 4))
+	policies := map[string]func() Policy[int]{
+		"LRU":  func() Policy[int] { return nil },
+		"FIFO": NewFIFOPolicy[int],
+		"LFU":  NewLFUPolicy[int],
+		"2Q":   New2QPolicy[int],
+	}
+	for name, policy := range policies {
+		c := NewLRU(50, &CacheOptions[int, int]{Policy: policy()})
+		for range 20000 {
+			k := r.IntN(200)
+			switch r.IntN(10) {
+			case 0:
+				c.Delete(k)
+			case 1, 2, 3:
+				c.Get(k)
+			default:
+				c.Set(k, k)
+			}
+			if c.Len() > 50 {
+				t.Fatalf("%s: Len() = %d over the capacity", name, c.Len())
+			}
+		}
+
+		// the cache must give up all its keys, and no others
+		var victims []int
+		for c.Len() > 0 {
+			k, ok := c.victim()
+			if !ok || !c.Has(k) {
+				t.Fatalf("%s: victim() = %d, %v, which is not in the cache", name, k, ok)
+			}
+			victims = append(victims, k)
+			c.Delete(k)
+		}
+		if _, ok := c.victim(); ok || len(victims) == 0 {
+			t.Errorf("%s: victim() of an empty cache returned a key", name)
+		}
+	}
+}
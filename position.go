@@ -0,0 +1,117 @@
+package omap
+
+// InsertBefore inserts a key-value pair immediately before the mark key.
+// It returns false if the mark does not exist or the key already exists.
+func (m *Map[K, V]) InsertBefore(mark K, key K, value V) bool {
+	at, ok := m.kv[mark]
+	if !ok {
+		return false
+	}
+	return m.insertAfter(at.prev, key, value)
+}
+
+// InsertAfter inserts a key-value pair immediately after the mark key.
+// It returns false if the mark does not exist or the key already exists.
+func (m *Map[K, V]) InsertAfter(mark K, key K, value V) bool {
+	at, ok := m.kv[mark]
+	if !ok {
+		return false
+	}
+	return m.insertAfter(at, key, value)
+}
+
+func (m *Map[K, V]) insertAfter(at *Element[K, V], key K, value V) bool {
+	if _, exists := m.kv[key]; exists {
+		return false
+	}
+	m.kv[key] = m.kl.insert(&Element[K, V]{key: key, val: value}, at)
+	return true
+}
+
+// MoveToFront moves the key to the front of the map.
+// It returns false if the key does not exist.
+func (m *Map[K, V]) MoveToFront(key K) bool {
+	e, ok := m.kv[key]
+	if !ok {
+		return false
+	}
+	m.kl.move(e, &m.kl.root)
+	return true
+}
+
+// MoveToBack moves the key to the back of the map.
+// It returns false if the key does not exist.
+func (m *Map[K, V]) MoveToBack(key K) bool {
+	e, ok := m.kv[key]
+	if !ok {
+		return false
+	}
+	m.kl.move(e, m.kl.root.prev)
+	return true
+}
+
+// MoveBefore moves the key to the position immediately before the mark key.
+// It returns false if either the key or the mark does not exist.
+func (m *Map[K, V]) MoveBefore(key K, mark K) bool {
+	e, ok := m.kv[key]
+	if !ok {
+		return false
+	}
+	at, ok := m.kv[mark]
+	if !ok {
+		return false
+	}
+	if e != at {
+		m.kl.move(e, at.prev)
+	}
+	return true
+}
+
+// MoveAfter moves the key to the position immediately after the mark key.
+// It returns false if either the key or the mark does not exist.
+func (m *Map[K, V]) MoveAfter(key K, mark K) bool {
+	e, ok := m.kv[key]
+	if !ok {
+		return false
+	}
+	at, ok := m.kv[mark]
+	if !ok {
+		return false
+	}
+	m.kl.move(e, at)
+	return true
+}
+
+// Swap exchanges the positions of two keys, leaving their values untouched.
+// It returns false if either key does not exist.
+func (m *Map[K, V]) Swap(k1, k2 K) bool {
+	e1, ok := m.kv[k1]
+	if !ok {
+		return false
+	}
+	e2, ok := m.kv[k2]
+	if !ok {
+		return false
+	}
+	m.kl.swap(e1, e2)
+	return true
+}
+
+// Rename changes the key of an entry to a new key while keeping its value and position.
+// It returns false if the old key does not exist or the new key already exists.
+func (m *Map[K, V]) Rename(oldKey, newKey K) bool {
+	e, ok := m.kv[oldKey]
+	if !ok {
+		return false
+	}
+	if oldKey == newKey {
+		return true
+	}
+	if _, exists := m.kv[newKey]; exists {
+		return false
+	}
+	delete(m.kv, oldKey)
+	e.key = newKey
+	m.kv[newKey] = e
+	return true
+}
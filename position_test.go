@@ -0,0 +1,204 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+)
+
+func newABC() *Map[string, int] {
+	m := New[string, int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+	return m
+}
+
+func TestMap_InsertBefore(t *testing.T) {
+	m := newABC()
+
+	if !m.InsertBefore("a", "x", 10) {
+		t.Error("InsertBefore(a, x) should succeed")
+	}
+	if !m.InsertBefore("c", "y", 20) {
+		t.Error("InsertBefore(c, y) should succeed")
+	}
+	want := []string{"x", "a", "b", "y", "c"}
+	if !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+	if val := m.Get("y"); val != 20 {
+		t.Errorf("Get(y) = %v, want 20", val)
+	}
+
+	if m.InsertBefore("missing", "z", 0) {
+		t.Error("InsertBefore with missing mark should fail")
+	}
+	if m.InsertBefore("a", "b", 0) {
+		t.Error("InsertBefore with existing key should fail")
+	}
+	if m.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", m.Len())
+	}
+}
+
+func TestMap_InsertAfter(t *testing.T) {
+	m := newABC()
+
+	if !m.InsertAfter("c", "x", 10) {
+		t.Error("InsertAfter(c, x) should succeed")
+	}
+	if !m.InsertAfter("a", "y", 20) {
+		t.Error("InsertAfter(a, y) should succeed")
+	}
+	want := []string{"a", "y", "b", "c", "x"}
+	if !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+	if m.Back().Key() != "x" {
+		t.Errorf("Back() = %v, want x", m.Back().Key())
+	}
+
+	if m.InsertAfter("missing", "z", 0) {
+		t.Error("InsertAfter with missing mark should fail")
+	}
+	if m.InsertAfter("a", "c", 0) {
+		t.Error("InsertAfter with existing key should fail")
+	}
+}
+
+func TestMap_MoveToFront_MoveToBack(t *testing.T) {
+	m := newABC()
+
+	if !m.MoveToFront("c") {
+		t.Error("MoveToFront(c) should succeed")
+	}
+	if want := []string{"c", "a", "b"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() after MoveToFront = %v, want %v", m.Keys(), want)
+	}
+
+	if !m.MoveToBack("c") {
+		t.Error("MoveToBack(c) should succeed")
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() after MoveToBack = %v, want %v", m.Keys(), want)
+	}
+
+	// Moving in place is a no-op
+	m.MoveToFront("a")
+	m.MoveToBack("c")
+	if want := []string{"a", "b", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() after no-op moves = %v, want %v", m.Keys(), want)
+	}
+	if want := []int{1, 2, 3}; !slices.Equal(m.Values(), want) {
+		t.Errorf("Values() after moves = %v, want %v", m.Values(), want)
+	}
+
+	if m.MoveToFront("missing") || m.MoveToBack("missing") {
+		t.Error("moving a missing key should fail")
+	}
+}
+
+func TestMap_MoveBefore_MoveAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		move  func(m *Map[string, int]) bool
+		want  []string
+		moved bool
+	}{
+		{"Before first", func(m *Map[string, int]) bool { return m.MoveBefore("c", "a") }, []string{"c", "a", "b"}, true},
+		{"Before next", func(m *Map[string, int]) bool { return m.MoveBefore("a", "b") }, []string{"a", "b", "c"}, true},
+		{"Before prev", func(m *Map[string, int]) bool { return m.MoveBefore("b", "a") }, []string{"b", "a", "c"}, true},
+		{"Before self", func(m *Map[string, int]) bool { return m.MoveBefore("b", "b") }, []string{"a", "b", "c"}, true},
+		{"After last", func(m *Map[string, int]) bool { return m.MoveAfter("a", "c") }, []string{"b", "c", "a"}, true},
+		{"After prev", func(m *Map[string, int]) bool { return m.MoveAfter("b", "a") }, []string{"a", "b", "c"}, true},
+		{"After next", func(m *Map[string, int]) bool { return m.MoveAfter("a", "b") }, []string{"b", "a", "c"}, true},
+		{"After self", func(m *Map[string, int]) bool { return m.MoveAfter("b", "b") }, []string{"a", "b", "c"}, true},
+		{"Missing key", func(m *Map[string, int]) bool { return m.MoveBefore("x", "a") }, []string{"a", "b", "c"}, false},
+		{"Missing mark", func(m *Map[string, int]) bool { return m.MoveAfter("a", "x") }, []string{"a", "b", "c"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newABC()
+			if moved := tt.move(m); moved != tt.moved {
+				t.Errorf("move returned %v, want %v", moved, tt.moved)
+			}
+			if !slices.Equal(m.Keys(), tt.want) {
+				t.Errorf("Keys() = %v, want %v", m.Keys(), tt.want)
+			}
+			var back []string
+			for e := m.Back(); e != nil; e = e.Prev() {
+				back = append(back, e.Key())
+			}
+			slices.Reverse(back)
+			if !slices.Equal(back, tt.want) {
+				t.Errorf("backward keys = %v, want %v", back, tt.want)
+			}
+		})
+	}
+}
+
+func TestMap_Swap(t *testing.T) {
+	tests := []struct {
+		name   string
+		k1, k2 string
+		want   []string
+	}{
+		{"Ends", "a", "c", []string{"c", "b", "a"}},
+		{"Adjacent", "a", "b", []string{"b", "a", "c"}},
+		{"Adjacent reversed", "c", "b", []string{"a", "c", "b"}},
+		{"Same", "b", "b", []string{"a", "b", "c"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newABC()
+			if !m.Swap(tt.k1, tt.k2) {
+				t.Fatalf("Swap(%s, %s) should succeed", tt.k1, tt.k2)
+			}
+			if !slices.Equal(m.Keys(), tt.want) {
+				t.Errorf("Keys() = %v, want %v", m.Keys(), tt.want)
+			}
+			for _, k := range tt.want {
+				if m.Get(k) != int(k[0]-'a'+1) {
+					t.Errorf("Get(%s) = %v, value should follow key", k, m.Get(k))
+				}
+			}
+		})
+	}
+
+	m := newABC()
+	if m.Swap("a", "missing") {
+		t.Error("Swap with missing key should fail")
+	}
+}
+
+func TestMap_Rename(t *testing.T) {
+	m := newABC()
+
+	if !m.Rename("b", "x") {
+		t.Error("Rename(b, x) should succeed")
+	}
+	if want := []string{"a", "x", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+	if val, ok := m.TryGet("x"); !ok || val != 2 {
+		t.Errorf("TryGet(x) = (%v, %v), want (2, true)", val, ok)
+	}
+	if m.Has("b") {
+		t.Error("Has(b) after Rename = true, want false")
+	}
+
+	if !m.Rename("a", "a") {
+		t.Error("Rename to the same key should succeed")
+	}
+	if m.Rename("a", "c") {
+		t.Error("Rename to an existing key should fail")
+	}
+	if m.Rename("missing", "y") {
+		t.Error("Rename of a missing key should fail")
+	}
+	if m.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", m.Len())
+	}
+}
@@ -0,0 +1,66 @@
+package omap
+
+// First returns the first key-value pair of the map.
+// The ok result is false if the map is empty.
+func (m *Map[K, V]) First() (key K, value V, ok bool) {
+	if len(m.kv) == 0 {
+		return key, value, false
+	}
+	e := m.kl.root.next
+	return e.key, e.val, true
+}
+
+// Last returns the last key-value pair of the map.
+// The ok result is false if the map is empty.
+func (m *Map[K, V]) Last() (key K, value V, ok bool) {
+	if len(m.kv) == 0 {
+		return key, value, false
+	}
+	e := m.kl.root.prev
+	return e.key, e.val, true
+}
+
+// PopFirst removes and returns the first key-value pair of the map.
+// The ok result is false if the map is empty.
+func (m *Map[K, V]) PopFirst() (key K, value V, ok bool) {
+	if len(m.kv) == 0 {
+		return key, value, false
+	}
+	e := m.kl.root.next
+	m.kl.delete(e)
+	delete(m.kv, e.key)
+	return e.key, e.val, true
+}
+
+// PopLast removes and returns the last key-value pair of the map.
+// The ok result is false if the map is empty.
+func (m *Map[K, V]) PopLast() (key K, value V, ok bool) {
+	if len(m.kv) == 0 {
+		return key, value, false
+	}
+	e := m.kl.root.prev
+	m.kl.delete(e)
+	delete(m.kv, e.key)
+	return e.key, e.val, true
+}
+
+// PopFirstN removes and returns up to n entries from the front of the map, in order.
+func (m *Map[K, V]) PopFirstN(n int) []Entry[K, V] {
+	n = min(n, len(m.kv))
+	if n <= 0 {
+		return nil
+	}
+
+	entries := make([]Entry[K, V], 0, n)
+	first := m.kl.root.next
+	last := first
+	for {
+		entries = append(entries, Entry[K, V]{Key: last.key, Value: last.val})
+		if len(entries) == n {
+			break
+		}
+		last = last.next
+	}
+	m.deleteRun(first, last, n)
+	return entries
+}
@@ -0,0 +1,80 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestMap_First_Last(t *testing.T) {
+	var m Map[string, int]
+	if _, _, ok := m.First(); ok {
+		t.Error("First() on zero map should report false")
+	}
+	if _, _, ok := m.Last(); ok {
+		t.Error("Last() on zero map should report false")
+	}
+
+	m.Set("a", 1)
+	m.Set("b", 2)
+
+	if k, v, ok := m.First(); k != "a" || v != 1 || !ok {
+		t.Errorf("First() = (%v, %v, %v), want (a, 1, true)", k, v, ok)
+	}
+	if k, v, ok := m.Last(); k != "b" || v != 2 || !ok {
+		t.Errorf("Last() = (%v, %v, %v), want (b, 2, true)", k, v, ok)
+	}
+	if m.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", m.Len())
+	}
+}
+
+func TestMap_PopFirst_PopLast(t *testing.T) {
+	m := newABC()
+
+	if k, v, ok := m.PopFirst(); k != "a" || v != 1 || !ok {
+		t.Errorf("PopFirst() = (%v, %v, %v), want (a, 1, true)", k, v, ok)
+	}
+	if k, v, ok := m.PopLast(); k != "c" || v != 3 || !ok {
+		t.Errorf("PopLast() = (%v, %v, %v), want (c, 3, true)", k, v, ok)
+	}
+	if want := []string{"b"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+
+	m.PopLast()
+	if _, _, ok := m.PopFirst(); ok {
+		t.Error("PopFirst() on empty map should report false")
+	}
+	if _, _, ok := m.PopLast(); ok {
+		t.Error("PopLast() on empty map should report false")
+	}
+
+	// Re-adding a popped key appends it
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.PopFirst()
+	m.Set("a", 1)
+	if want := []string{"b", "a"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+}
+
+func TestMap_PopFirstN(t *testing.T) {
+	m := newABC()
+
+	got := m.PopFirstN(2)
+	if want := []Entry[string, int]{{"a", 1}, {"b", 2}}; !slices.Equal(got, want) {
+		t.Errorf("PopFirstN(2) = %v, want %v", got, want)
+	}
+	if want := []string{"c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", m.Keys(), want)
+	}
+
+	got = m.PopFirstN(5)
+	if want := []Entry[string, int]{{"c", 3}}; !slices.Equal(got, want) {
+		t.Errorf("PopFirstN(5) = %v, want %v", got, want)
+	}
+	if got := m.PopFirstN(1); got != nil {
+		t.Errorf("PopFirstN(1) on empty map = %v, want nil", got)
+	}
+}
@@ -0,0 +1,76 @@
+package omap
+
+// Union returns a new map with the keys of a in the order of a, followed by the keys
+// that only b has in the order of b. For keys in both maps, resolve is called with the
+// value of a and the value of b, and its result is stored. A nil resolve takes the value
+// of b, like Merge. A nil map is treated as empty.
+func Union[K comparable, V any](a, b *Map[K, V], resolve func(key K, a, b V) V) *Map[K, V] {
+	a, b = orEmpty(a), orEmpty(b)
+	m := Make[K, V](a.Len() + b.Len())
+	for k, v := range a.All() {
+		if e, ok := b.kv[k]; ok {
+			v = pick(k, v, e.val, resolve)
+		}
+		m.kv[k] = m.kl.append(k, v)
+	}
+	for k, v := range b.All() {
+		if _, ok := a.kv[k]; !ok {
+			m.kv[k] = m.kl.append(k, v)
+		}
+	}
+	return m
+}
+
+// Intersect returns a new map with the keys that a and b have in common, in the order
+// of a. Values are chosen by resolve as in Union.
+func Intersect[K comparable, V any](a, b *Map[K, V], resolve func(key K, a, b V) V) *Map[K, V] {
+	a, b = orEmpty(a), orEmpty(b)
+	m := Make[K, V](min(a.Len(), b.Len()))
+	for k, v := range a.All() {
+		if e, ok := b.kv[k]; ok {
+			m.kv[k] = m.kl.append(k, pick(k, v, e.val, resolve))
+		}
+	}
+	return m
+}
+
+// Difference returns a new map with the entries of a whose keys b does not have,
+// in the order of a.
+func Difference[K comparable, V any](a, b *Map[K, V]) *Map[K, V] {
+	a, b = orEmpty(a), orEmpty(b)
+	m := New[K, V]()
+	for k, v := range a.All() {
+		if _, ok := b.kv[k]; !ok {
+			m.kv[k] = m.kl.append(k, v)
+		}
+	}
+	return m
+}
+
+// SymmetricDifference returns a new map with the entries whose keys only one of the maps has:
+// those of a in the order of a, followed by those of b in the order of b.
+func SymmetricDifference[K comparable, V any](a, b *Map[K, V]) *Map[K, V] {
+	a, b = orEmpty(a), orEmpty(b)
+	m := Difference(a, b)
+	for k, v := range b.All() {
+		if _, ok := a.kv[k]; !ok {
+			m.kv[k] = m.kl.append(k, v)
+		}
+	}
+	return m
+}
+
+// pick chooses the value for a key present in both operands of a set operation.
+func pick[K comparable, V any](key K, a, b V, resolve func(key K, a, b V) V) V {
+	if resolve == nil {
+		return b
+	}
+	return resolve(key, a, b)
+}
+
+func orEmpty[K comparable, V any](m *Map[K, V]) *Map[K, V] {
+	if m == nil {
+		return New[K, V]()
+	}
+	return m
+}
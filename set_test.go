@@ -0,0 +1,77 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestUnion(t *testing.T) {
+	a := mapOf[int]("a", 1, "b", 2, "c", 3)
+	b := mapOf[int]("d", 40, "b", 20, "e", 50)
+
+	m := Union(a, b, nil)
+	if want := mapOf[int]("a", 1, "b", 20, "c", 3, "d", 40, "e", 50); !m.Equal(want) {
+		t.Errorf("Union() = %v, want %v", m.Keys(), want.Keys())
+	}
+
+	m = Union(a, b, func(key string, a, b int) int { return a + b })
+	if got := m.Get("b"); got != 22 {
+		t.Errorf("Union() with resolver: b = %d, want 22", got)
+	}
+
+	m = Union(nil, b, nil)
+	if !m.Equal(b) {
+		t.Errorf("Union(nil, b) = %v, want %v", m.Keys(), b.Keys())
+	}
+	if m == b {
+		t.Error("Union() returned an operand")
+	}
+}
+
+func TestIntersect(t *testing.T) {
+	a := mapOf[int]("a", 1, "b", 2, "c", 3, "d", 4)
+	b := mapOf[int]("d", 40, "x", 0, "b", 20)
+
+	m := Intersect(a, b, nil)
+	if want := mapOf[int]("b", 20, "d", 40); !m.Equal(want) {
+		t.Errorf("Intersect() = %v, want %v", m.Keys(), want.Keys())
+	}
+
+	m = Intersect(a, b, func(key string, a, b int) int { return a })
+	if want := mapOf[int]("b", 2, "d", 4); !m.Equal(want) {
+		t.Errorf("Intersect() with resolver = %v", m.Values())
+	}
+
+	if m = Intersect(a, nil, nil); m.Len() != 0 {
+		t.Errorf("Intersect(a, nil).Len() = %d, want 0", m.Len())
+	}
+}
+
+func TestDifference(t *testing.T) {
+	a := mapOf[int]("a", 1, "b", 2, "c", 3, "d", 4)
+	b := mapOf[int]("d", 40, "x", 0, "b", 20)
+
+	m := Difference(a, b)
+	if want := mapOf[int]("a", 1, "c", 3); !m.Equal(want) {
+		t.Errorf("Difference() = %v, want %v", m.Keys(), want.Keys())
+	}
+	if m = Difference(a, nil); !m.Equal(a) {
+		t.Errorf("Difference(a, nil) = %v, want %v", m.Keys(), a.Keys())
+	}
+	if m = Difference(nil, a); m.Len() != 0 {
+		t.Errorf("Difference(nil, a).Len() = %d, want 0", m.Len())
+	}
+}
+
+func TestSymmetricDifference(t *testing.T) {
+	a := mapOf[int]("a", 1, "b", 2, "c", 3, "d", 4)
+	b := mapOf[int]("y", 0, "d", 40, "x", 0, "b", 20)
+
+	m := SymmetricDifference(a, b)
+	if want := []string{"a", "c", "y", "x"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("SymmetricDifference() = %v, want %v", m.Keys(), want)
+	}
+	if m = SymmetricDifference(a, a); m.Len() != 0 {
+		t.Errorf("SymmetricDifference(a, a).Len() = %d, want 0", m.Len())
+	}
+}
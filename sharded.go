@@ -0,0 +1,305 @@
+package omap
+
+import (
+	"container/heap"
+	"hash/maphash"
+	"iter"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// ShardedMap is an ordered map for write-heavy concurrent workloads. It spreads
+// the keys across independently locked shards, so writers of different keys rarely
+// contend.
+//
+// Every new key takes a sequence number from a global counter. Like in Map, updating
+// an existing key keeps its position. Iteration merges the shards by sequence number,
+// so it yields the entries in insertion order. All, Range, Len and the marshaling
+// methods read a consistent cut of all shards: they lock every shard for reading
+// while they copy the entries or count them, and release the locks before yielding.
+//
+// The zero value is an empty map with a default number of shards, ready to use.
+// A ShardedMap must not be copied after first use.
+type ShardedMap[K comparable, V any] struct {
+	once   sync.Once
+	seed   maphash.Seed
+	shards []shard[K, V]
+	seq    atomic.Uint64
+}
+
+type shard[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  Map[K, sequenced[V]]
+}
+
+// sequenced is a value tagged with the sequence number of its key.
+type sequenced[V any] struct {
+	seq uint64
+	val V
+}
+
+// NewSharded creates and returns a new ShardedMap with n shards.
+// If n is not positive, a default based on GOMAXPROCS is used.
+func NewSharded[K comparable, V any](n int) *ShardedMap[K, V] {
+	s := &ShardedMap[K, V]{}
+	s.once.Do(func() {
+		s.init(n)
+	})
+	return s
+}
+
+func (s *ShardedMap[K, V]) init(n int) {
+	if n <= 0 {
+		n = 4 * runtime.GOMAXPROCS(0)
+	}
+	s.seed = maphash.MakeSeed()
+	s.shards = make([]shard[K, V], n)
+}
+
+func (s *ShardedMap[K, V]) lazyInit() {
+	s.once.Do(func() {
+		s.init(0)
+	})
+}
+
+func (s *ShardedMap[K, V]) shard(key K) *shard[K, V] {
+	s.lazyInit()
+	return &s.shards[maphash.Comparable(s.seed, key)%uint64(len(s.shards))]
+}
+
+// set adds a new key to sh, whose lock must be held. The sequence number is taken
+// under the lock, so every shard stays ordered by sequence number.
+func (s *ShardedMap[K, V]) set(sh *shard[K, V], key K, value V) {
+	sh.m.Set(key, sequenced[V]{seq: s.seq.Add(1), val: value})
+}
+
+// Set adds a key-value pair to the map, or updates the value of an existing key.
+func (s *ShardedMap[K, V]) Set(key K, value V) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if e := sh.m.GetElement(key); e != nil {
+		e.val.val = value
+	} else {
+		s.set(sh, key, value)
+	}
+}
+
+// TrySet adds a key-value pair to the map only if the key does not already exist.
+// It returns true if the key-value pair was added.
+func (s *ShardedMap[K, V]) TrySet(key K, value V) bool {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if sh.m.Has(key) {
+		return false
+	}
+	s.set(sh, key, value)
+	return true
+}
+
+// Get retrieves the value associated with the given key.
+func (s *ShardedMap[K, V]) Get(key K) V {
+	v, _ := s.TryGet(key)
+	return v
+}
+
+// TryGet retrieves the value associated with the given key.
+// It returns the value and true if the key exists, otherwise the zero value and false.
+func (s *ShardedMap[K, V]) TryGet(key K) (V, bool) {
+	sh := s.shard(key)
+	sh.mu.RLock()
+	defer sh.mu.RUnlock()
+	v, ok := sh.m.TryGet(key)
+	return v.val, ok
+}
+
+// Has checks if the map contains the given key.
+func (s *ShardedMap[K, V]) Has(key K) bool {
+	_, ok := s.TryGet(key)
+	return ok
+}
+
+// Delete removes the key-value pairs with the given keys.
+func (s *ShardedMap[K, V]) Delete(keys ...K) {
+	for _, k := range keys {
+		sh := s.shard(k)
+		sh.mu.Lock()
+		sh.m.Delete(k)
+		sh.mu.Unlock()
+	}
+}
+
+// Compute atomically updates the value of the key, as Map.Compute does.
+// fn runs while the shard of the key is locked and must not call methods of the map.
+func (s *ShardedMap[K, V]) Compute(key K, fn func(old V, exists bool) (V, bool)) (V, bool) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	e := sh.m.GetElement(key)
+	var old V
+	if e != nil {
+		old = e.val.val
+	}
+
+	value, keep := fn(old, e != nil)
+	switch {
+	case keep && e != nil:
+		e.val.val = value
+	case keep:
+		s.set(sh, key, value)
+	case e != nil:
+		sh.m.Remove(e)
+	}
+	if !keep {
+		var zero V
+		return zero, false
+	}
+	return value, true
+}
+
+// GetOrSet atomically returns the existing value of the key, or sets and returns the given value.
+// The loaded result is true if the value was loaded.
+func (s *ShardedMap[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	if v, ok := sh.m.TryGet(key); ok {
+		return v.val, true
+	}
+	s.set(sh, key, value)
+	return value, false
+}
+
+// LoadAndDelete atomically deletes the key and returns its value, if any.
+func (s *ShardedMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	sh := s.shard(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	v, ok := sh.m.LoadAndDelete(key)
+	return v.val, ok
+}
+
+// Clear removes all key-value pairs from the map. It locks all shards at once.
+func (s *ShardedMap[K, V]) Clear() {
+	s.lazyInit()
+	for i := range s.shards {
+		s.shards[i].mu.Lock()
+	}
+	for i := range s.shards {
+		s.shards[i].m.Clear()
+		s.shards[i].mu.Unlock()
+	}
+}
+
+// rlockAll locks all shards for reading, always in the same order, and returns a
+// function that releases them.
+func (s *ShardedMap[K, V]) rlockAll() func() {
+	s.lazyInit()
+	for i := range s.shards {
+		s.shards[i].mu.RLock()
+	}
+	return func() {
+		for i := range s.shards {
+			s.shards[i].mu.RUnlock()
+		}
+	}
+}
+
+// Len returns the number of key-value pairs in a consistent cut of the map.
+func (s *ShardedMap[K, V]) Len() int {
+	defer s.rlockAll()()
+	n := 0
+	for i := range s.shards {
+		n += s.shards[i].m.Len()
+	}
+	return n
+}
+
+// entries returns a consistent cut of the map in insertion order.
+func (s *ShardedMap[K, V]) entries() []Entry[K, V] {
+	unlock := s.rlockAll()
+	h := make(shardHeap[K, V], 0, len(s.shards))
+	n := 0
+	for i := range s.shards {
+		m := &s.shards[i].m
+		if e := m.Front(); e != nil {
+			h = append(h, e)
+			n += m.Len()
+		}
+	}
+
+	// merge the shards, each of which is ordered by sequence number
+	entries := make([]Entry[K, V], 0, n)
+	heap.Init(&h)
+	for len(h) > 0 {
+		e := h[0]
+		entries = append(entries, Entry[K, V]{Key: e.key, Value: e.val.val})
+		if h[0] = e.Next(); h[0] != nil {
+			heap.Fix(&h, 0)
+		} else {
+			heap.Pop(&h)
+		}
+	}
+	unlock()
+	return entries
+}
+
+// shardHeap is a min-heap of the next elements of the shards, ordered by sequence number.
+type shardHeap[K comparable, V any] []*Element[K, sequenced[V]]
+
+func (h shardHeap[K, V]) Len() int           { return len(h) }
+func (h shardHeap[K, V]) Less(i, j int) bool { return h[i].val.seq < h[j].val.seq }
+func (h shardHeap[K, V]) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *shardHeap[K, V]) Push(x any)        { *h = append(*h, x.(*Element[K, sequenced[V]])) }
+func (h *shardHeap[K, V]) Pop() any {
+	old := *h
+	e := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return e
+}
+
+// All returns an iterator over a consistent cut of the map in insertion order.
+// The shards are not locked while the loop body runs.
+func (s *ShardedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		yieldEntries(s.entries(), yield)
+	}
+}
+
+// Range calls f for each entry of a consistent cut of the map in insertion order,
+// until f returns false. The shards are not locked while f runs.
+func (s *ShardedMap[K, V]) Range(f func(key K, value V) bool) {
+	yieldEntries(s.entries(), f)
+}
+
+// Keys returns the keys of a consistent cut of the map in insertion order.
+func (s *ShardedMap[K, V]) Keys() []K {
+	entries := s.entries()
+	keys := make([]K, len(entries))
+	for i, e := range entries {
+		keys[i] = e.Key
+	}
+	return keys
+}
+
+// Values returns the values of a consistent cut of the map in insertion order.
+func (s *ShardedMap[K, V]) Values() []V {
+	entries := s.entries()
+	values := make([]V, len(entries))
+	for i, e := range entries {
+		values[i] = e.Value
+	}
+	return values
+}
+
+// Snapshot returns a consistent cut of the map as a Map.
+func (s *ShardedMap[K, V]) Snapshot() *Map[K, V] {
+	entries := s.entries()
+	m := Make[K, V](len(entries))
+	for _, e := range entries {
+		m.kv[e.Key] = m.kl.append(e.Key, e.Value)
+	}
+	return m
+}
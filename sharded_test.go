@@ -0,0 +1,135 @@
+package omap
+
+import (
+	"encoding/json"
+	"slices"
+	"sync"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+)
+
+func TestShardedMap(t *testing.T) {
+	var s ShardedMap[int, int] // zero value is ready to use
+	for i := range 100 {
+		s.Set(i, i)
+	}
+	s.Set(0, 100) // keeps its position
+	s.Delete(1)
+	s.Set(1, 1) // moves to the end
+	if !s.TrySet(200, 200) || s.TrySet(2, 0) {
+		t.Error("TrySet() returned the wrong result")
+	}
+
+	want := []int{0}
+	for i := 2; i < 100; i++ {
+		want = append(want, i)
+	}
+	want = append(want, 1, 200)
+	if got := s.Keys(); !slices.Equal(got, want) {
+		t.Errorf("Keys() = %v, want %v", got, want)
+	}
+	if got := s.Values(); got[0] != 100 || got[len(got)-2] != 1 {
+		t.Errorf("Values() = %v", got)
+	}
+	if s.Len() != 101 || s.Get(0) != 100 || !s.Has(200) || s.Has(300) {
+		t.Errorf("Len() = %d, Get(0) = %d", s.Len(), s.Get(0))
+	}
+	if got := s.Snapshot(); !slices.Equal(got.Keys(), want) {
+		t.Errorf("Snapshot() = %v", got.Keys())
+	}
+
+	var ranged []int
+	s.Range(func(k, v int) bool {
+		ranged = append(ranged, k)
+		return len(ranged) < 3
+	})
+	if !slices.Equal(ranged, []int{0, 2, 3}) {
+		t.Errorf("Range() = %v", ranged)
+	}
+
+	if v, loaded := s.GetOrSet(5, 0); !loaded || v != 5 {
+		t.Errorf("GetOrSet(5) = %d, %v", v, loaded)
+	}
+	if v, ok := s.Compute(5, func(old int, _ bool) (int, bool) { return 0, false }); ok || v != 0 || s.Has(5) {
+		t.Errorf("Compute() deleting = %d, %v", v, ok)
+	}
+	if v, ok := s.LoadAndDelete(6); !ok || v != 6 || s.Has(6) {
+		t.Errorf("LoadAndDelete(6) = %d, %v", v, ok)
+	}
+
+	s.Clear()
+	if s.Len() != 0 || len(s.Keys()) != 0 {
+		t.Errorf("Len() after Clear() = %d", s.Len())
+	}
+}
+
+func TestShardedMap_ConsistentCut(t *testing.T) {
+	s := NewSharded[int, int](8)
+	const n = 5000
+
+	var wg sync.WaitGroup
+	wg.Go(func() {
+		for i := range n {
+			s.Set(i, i)
+		}
+	})
+	for range 4 {
+		wg.Go(func() {
+			for range 50 {
+				// a single writer inserts in order, so every cut is a prefix
+				i := 0
+				for k := range s.All() {
+					if k < 0 {
+						continue
+					}
+					if k != i {
+						t.Errorf("cut has key %d at position %d", k, i)
+						return
+					}
+					i++
+				}
+			}
+		})
+	}
+	for w := range 4 {
+		wg.Go(func() {
+			for i := range 500 {
+				s.Compute(-1-w, func(old int, _ bool) (int, bool) {
+					return old + i, true
+				})
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := s.Len(); got != n+4 {
+		t.Errorf("Len() = %d, want %d", got, n+4)
+	}
+}
+
+func TestShardedMap_Encoding(t *testing.T) {
+	s := NewSharded[string, int](4)
+	if err := json.Unmarshal([]byte(`{"b":2,"a":1,"c":3}`), s); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"b":2,"a":1,"c":3}`; string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+
+	s = NewSharded[string, int](0)
+	if err := yaml.Unmarshal([]byte("b: 2\na: 1\n"), s); err != nil {
+		t.Fatal(err)
+	}
+	b, err = yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "b: 2\na: 1\n"; string(b) != want {
+		t.Errorf("yaml.Marshal() = %q, want %q", b, want)
+	}
+}
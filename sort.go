@@ -1,106 +1,274 @@
 package omap
 
-import "cmp"
+import (
+	"cmp"
+	"runtime"
+	"slices"
+	"sync"
+)
 
 // Sort sorts the map in ascending order of keys.
-func Sort[K cmp.Ordered, V any](m *Map[K, V]) {
+// It panics if m is a SortedMap, which keeps its own order.
+func Sort[K cmp.Ordered, V any](m OrderedMap[K, V]) {
 	SortFunc(m, cmp.Compare)
 }
 
 // SortDesc sorts the map in descending order of keys.
-func SortDesc[K cmp.Ordered, V any](m *Map[K, V]) {
+// It panics if m is a SortedMap, which keeps its own order.
+func SortDesc[K cmp.Ordered, V any](m OrderedMap[K, V]) {
 	SortFunc(m, func(k1, k2 K) int {
 		return cmp.Compare(k2, k1)
 	})
 }
 
 // SortFunc sorts the map using a custom comparison function for keys.
-func SortFunc[K cmp.Ordered, V any](m *Map[K, V], compare func(k1, k2 K) int) {
-	if m == nil || m.Len() < 2 {
+// The sort is stable.
+// It panics if m is a SortedMap, which keeps its own order.
+func SortFunc[K comparable, V any](m OrderedMap[K, V], compare func(k1, k2 K) int) {
+	sortMap(m, func(a, b *Element[K, V]) int {
+		return compare(a.key, b.key)
+	})
+}
+
+// SortByValue sorts the map in ascending order of values.
+// The sort is stable.
+// It panics if m is a SortedMap, which keeps its own order.
+func SortByValue[K comparable, V cmp.Ordered](m OrderedMap[K, V]) {
+	SortByValueFunc(m, cmp.Compare)
+}
+
+// SortByValueFunc sorts the map using a custom comparison function for values.
+// The sort is stable.
+// It panics if m is a SortedMap, which keeps its own order.
+func SortByValueFunc[K comparable, V any](m OrderedMap[K, V], compare func(v1, v2 V) int) {
+	sortMap(m, func(a, b *Element[K, V]) int {
+		return compare(a.val, b.val)
+	})
+}
+
+// SortEntriesFunc sorts the map using a custom comparison function for entries.
+// The sort is stable.
+// It panics if m is a SortedMap, which keeps its own order.
+func SortEntriesFunc[K comparable, V any](m OrderedMap[K, V], compare func(a, b Entry[K, V]) int) {
+	sortMap(m, func(a, b *Element[K, V]) int {
+		return compare(Entry[K, V]{Key: a.key, Value: a.val}, Entry[K, V]{Key: b.key, Value: b.val})
+	})
+}
+
+// SortParallel sorts the map like SortFunc, but splits the list into chunks that are
+// sorted on separate goroutines and then merged. The result is identical to SortFunc.
+// compare must be safe for concurrent use. Maps too small to benefit, and other
+// implementations of OrderedMap, are sorted on the calling goroutine.
+// It panics if m is a SortedMap, which keeps its own order.
+func SortParallel[K comparable, V any](m OrderedMap[K, V], compare func(k1, k2 K) int) {
+	less := func(a, b *Element[K, V]) int {
+		return compare(a.key, b.key)
+	}
+	switch m := m.(type) {
+	case *Map[K, V]:
+		sortParallel(m, less)
+	case *SyncMap[K, V]:
+		defer m.write()()
+		sortParallel(&m.m, less)
+	case *COWMap[K, V]:
+		m.Update(func(m *Map[K, V]) {
+			sortParallel(m, less)
+		})
+	default:
+		sortMap(m, less)
+	}
+}
+
+func sortParallel[K comparable, V any](m *Map[K, V], compare func(a, b *Element[K, V]) int) {
+	workers := min(runtime.GOMAXPROCS(0), m.Len()/minParallelChunk)
+	if workers < 2 {
+		sortList(m, compare)
 		return
 	}
+	m.kl.check()
 
-	// detach the list from root for sorting
-	head := m.kl.root.next
-	tail := m.kl.root.prev
+	// cut the list into consecutive chunks of about the same size
+	runs := make([]run[K, V], workers)
+	head := m.kl.detach()
+	size := m.Len() / workers
+	for i := range runs {
+		n := size
+		if i == workers-1 {
+			n = m.Len() - size*(workers-1)
+		}
+		tail := head
+		for range n - 1 {
+			tail = tail.next
+		}
+		runs[i] = run[K, V]{head: head, tail: tail}
+		head = tail.next
+		tail.next = nil
+	}
 
-	// disconnect the circular links
-	head.prev = nil
-	tail.next = nil
+	var wg sync.WaitGroup
+	for i := range runs {
+		wg.Go(func() {
+			runs[i] = sortRun(runs[i].head, compare)
+		})
+	}
+	wg.Wait()
 
-	// perform merge sort
-	sorted := mergeSortList(head, compare)
+	// merge neighbouring runs pairwise, keeping earlier runs on the left for stability
+	for len(runs) > 1 {
+		merged := runs[:(len(runs)+1)/2]
+		for i := 0; i+1 < len(runs); i += 2 {
+			wg.Go(func() {
+				runs[i] = mergeRuns(runs[i], runs[i+1], compare)
+			})
+		}
+		wg.Wait()
+		for i := range merged {
+			merged[i] = runs[2*i]
+		}
+		runs = merged
+	}
+	m.kl.attach(runs[0])
+}
 
-	// reconnect the sorted list back to root
-	m.kl.root.next = sorted
-	sorted.prev = &m.kl.root
+// minParallelChunk is the smallest number of elements SortParallel hands to a goroutine.
+const minParallelChunk = 1 << 14
 
-	// find the new tail and reconnect
-	last := sorted
-	for last.next != nil {
-		last = last.next
-	}
-	last.next = &m.kl.root
-	m.kl.root.prev = last
+// run is a sorted, nil-terminated sublist with known ends.
+type run[K comparable, V any] struct {
+	head, tail *Element[K, V]
 }
 
-func mergeSortList[K cmp.Ordered, V any](head *elem[K, V], compare func(k1, k2 K) int) *elem[K, V] {
-	if head == nil || head.next == nil {
-		return head
-	}
+// sortMap sorts a Map in place. Other implementations of OrderedMap are sorted by
+// deleting and setting their entries again, except for SortedMap, which keeps its
+// own order and panics.
+func sortMap[K comparable, V any](m OrderedMap[K, V], compare func(a, b *Element[K, V]) int) {
+	switch m := m.(type) {
+	case nil:
+	case *SortedMap[K, V]:
+		panic("omap: cannot sort a SortedMap, which keeps its own order")
+	case *Map[K, V]:
+		sortList(m, compare)
+	case *SyncMap[K, V]:
+		defer m.write()()
+		sortList(&m.m, compare)
+	case *COWMap[K, V]:
+		m.Update(func(m *Map[K, V]) {
+			sortList(m, compare)
+		})
+	default:
+		entries := make([]Element[K, V], 0, m.Len())
+		for k, v := range m.All() {
+			entries = append(entries, Element[K, V]{key: k, val: v})
+		}
+		sorted := make([]*Element[K, V], len(entries))
+		for i := range entries {
+			sorted[i] = &entries[i]
+		}
+		slices.SortStableFunc(sorted, compare)
 
-	// split the list into two halves
-	mid, fast := head, head
-	for fast.next != nil && fast.next.next != nil {
-		mid = mid.next
-		fast = fast.next.next
+		for _, e := range sorted {
+			m.Delete(e.key)
+		}
+		for _, e := range sorted {
+			m.Set(e.key, e.val)
+		}
 	}
-	right := mid.next
-	mid.next = nil
-	if right != nil {
-		right.prev = nil
+}
+
+func sortList[K comparable, V any](m *Map[K, V], compare func(a, b *Element[K, V]) int) {
+	if m == nil || m.Len() < 2 {
+		return
 	}
+	m.kl.check()
+	m.kl.attach(sortRun(m.kl.detach(), compare))
+}
 
-	// recursively sort both halves
-	left := mergeSortList[K, V](head, compare)
-	right = mergeSortList[K, V](right, compare)
+// detach unlinks the elements from root and returns the first one.
+// The returned chain is terminated by nil in both directions.
+func (l *list[K, V]) detach() *Element[K, V] {
+	head, tail := l.root.next, l.root.prev
+	head.prev = nil
+	tail.next = nil
+	return head
+}
 
-	// merge the sorted halves
-	return mergeList[K, V](left, right, compare)
+// attach links the elements of r back to root.
+func (l *list[K, V]) attach(r run[K, V]) {
+	l.root.next = r.head
+	r.head.prev = &l.root
+	l.root.prev = r.tail
+	r.tail.next = &l.root
+	l.reordered()
 }
 
-func mergeList[K cmp.Ordered, V any](left, right *elem[K, V], compare func(k1, k2 K) int) *elem[K, V] {
-	// create a dummy head for the result list
-	var dummy elem[K, V]
-	tail := &dummy
+// sortRun sorts the nil-terminated chain starting at head with a bottom-up merge sort.
+// Like a binary counter, bins[i] holds either nothing or a sorted run of 2^i elements
+// taken from before all the elements that follow it, so the sort is stable, needs
+// no recursion and no allocation, and never walks the list to find midpoints or tails.
+func sortRun[K comparable, V any](head *Element[K, V], compare func(a, b *Element[K, V]) int) run[K, V] {
+	var bins [64]run[K, V]
+	for head != nil {
+		carry := run[K, V]{head: head, tail: head}
+		head = head.next
+		carry.head.prev = nil
+		carry.head.next = nil
 
-	for left != nil && right != nil {
-		if compare(left.key, right.key) <= 0 {
-			tail.next = left
-			left.prev = tail
-			left = left.next
-		} else {
-			tail.next = right
-			right.prev = tail
-			right = right.next
+		i := 0
+		for ; bins[i].head != nil; i++ {
+			carry = mergeRuns(bins[i], carry, compare)
+			bins[i] = run[K, V]{}
+		}
+		bins[i] = carry
+	}
+
+	// higher bins hold earlier elements
+	var r run[K, V]
+	for _, b := range bins {
+		switch {
+		case b.head == nil:
+		case r.head == nil:
+			r = b
+		default:
+			r = mergeRuns(b, r, compare)
 		}
-		tail = tail.next
 	}
+	return r
+}
 
-	// append the remaining nodes
-	if left != nil {
-		tail.next = left
-		left.prev = tail
+// mergeRuns merges two non-empty sorted runs. Elements of a come first among equal ones.
+func mergeRuns[K comparable, V any](a, b run[K, V], compare func(a, b *Element[K, V]) int) run[K, V] {
+	var head *Element[K, V]
+	if compare(a.head, b.head) <= 0 {
+		head, a.head = a.head, a.head.next
+	} else {
+		head, b.head = b.head, b.head.next
 	}
-	if right != nil {
-		tail.next = right
-		right.prev = tail
+	head.prev = nil
+
+	tail := head
+	for a.head != nil && b.head != nil {
+		if compare(a.head, b.head) <= 0 {
+			tail.next = a.head
+			a.head.prev = tail
+			a.head = a.head.next
+		} else {
+			tail.next = b.head
+			b.head.prev = tail
+			b.head = b.head.next
+		}
+		tail = tail.next
 	}
 
-	// fix the head's prev pointer
-	result := dummy.next
-	if result != nil {
-		result.prev = nil
+	// append the remaining run, whose tail becomes the tail of the result
+	switch {
+	case a.head != nil:
+		tail.next = a.head
+		a.head.prev = tail
+		tail = a.tail
+	case b.head != nil:
+		tail.next = b.head
+		b.head.prev = tail
+		tail = b.tail
 	}
-	return result
+	return run[K, V]{head: head, tail: tail}
 }
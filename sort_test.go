@@ -2,6 +2,7 @@ package omap
 
 import (
 	"cmp"
+	"runtime"
 	"slices"
 	"testing"
 )
@@ -101,3 +102,154 @@ func TestSortFunc(t *testing.T) {
 		t.Errorf("Stability check failed: expected [one, six, ...], got %v", keys)
 	}
 }
+
+func TestSortFunc_StructKeys(t *testing.T) {
+	type point struct{ x, y int }
+	m := New[point, string]()
+	m.Set(point{2, 1}, "c")
+	m.Set(point{1, 2}, "b")
+	m.Set(point{1, 1}, "a")
+
+	SortFunc(m, func(a, b point) int {
+		return cmp.Or(cmp.Compare(a.x, b.x), cmp.Compare(a.y, b.y))
+	})
+
+	if want := []string{"a", "b", "c"}; !slices.Equal(m.Values(), want) {
+		t.Errorf("SortFunc() values = %v, want %v", m.Values(), want)
+	}
+}
+
+func TestSortByValue(t *testing.T) {
+	m := New[string, int]()
+	m.Set("a", 3)
+	m.Set("b", 1)
+	m.Set("c", 2)
+	m.Set("d", 1)
+
+	SortByValue(m)
+
+	// "b" and "d" have equal values and keep their relative order
+	if want := []string{"b", "d", "c", "a"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("SortByValue() keys = %v, want %v", m.Keys(), want)
+	}
+	if want := []int{1, 1, 2, 3}; !slices.Equal(m.Values(), want) {
+		t.Errorf("SortByValue() values = %v, want %v", m.Values(), want)
+	}
+}
+
+func TestSortByValueFunc(t *testing.T) {
+	m := New[string, []int]()
+	m.Set("a", []int{1, 2, 3})
+	m.Set("b", []int{1})
+	m.Set("c", []int{1, 2})
+
+	SortByValueFunc(m, func(v1, v2 []int) int {
+		return cmp.Compare(len(v2), len(v1))
+	})
+
+	if want := []string{"a", "c", "b"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("SortByValueFunc() keys = %v, want %v", m.Keys(), want)
+	}
+}
+
+func TestSortEntriesFunc(t *testing.T) {
+	m := New[string, int]()
+	m.Set("b", 1)
+	m.Set("a", 2)
+	m.Set("c", 1)
+	m.Set("d", 2)
+
+	// Sort by value descending, then key ascending
+	SortEntriesFunc(m, func(a, b Entry[string, int]) int {
+		return cmp.Or(cmp.Compare(b.Value, a.Value), cmp.Compare(a.Key, b.Key))
+	})
+
+	if want := []string{"a", "d", "b", "c"}; !slices.Equal(m.Keys(), want) {
+		t.Errorf("SortEntriesFunc() keys = %v, want %v", m.Keys(), want)
+	}
+}
+
+func TestSortFunc_Stable(t *testing.T) {
+	m := New[int, int]()
+	var want []int
+	for i := range 10_000 {
+		m.Set(i, i*7919%101)
+		want = append(want, i)
+	}
+	byValue := func(a, b int) int {
+		return cmp.Compare(a*7919%101, b*7919%101)
+	}
+	slices.SortStableFunc(want, byValue)
+
+	SortFunc(m, byValue)
+	if !slices.Equal(m.Keys(), want) {
+		t.Fatal("SortFunc() is not stable")
+	}
+	checkLinks(t, m)
+}
+
+func TestSortParallel(t *testing.T) {
+	defer runtime.GOMAXPROCS(runtime.GOMAXPROCS(5))
+
+	for _, n := range []int{0, 1, 100, 5*minParallelChunk + 3} {
+		m := New[int, int]()
+		var want []int
+		for i := range n {
+			m.Set(i, i*7919%1009)
+			want = append(want, i)
+		}
+		m.IndexOf(0) // builds the index, which the sort must drop
+		byValue := func(a, b int) int {
+			return cmp.Compare(a*7919%1009, b*7919%1009)
+		}
+		slices.SortStableFunc(want, byValue)
+
+		SortParallel(m, byValue)
+		if !slices.Equal(m.Keys(), want) {
+			t.Fatalf("SortParallel() with %d entries differs from a stable sort", n)
+		}
+		checkLinks(t, m)
+		if n > 0 {
+			if k, _ := m.At(n - 1); k != want[n-1] {
+				t.Errorf("At(%d) = %d, want %d", n-1, k, want[n-1])
+			}
+		}
+	}
+}
+
+// checkLinks verifies that the backward links mirror the forward links.
+func checkLinks[K comparable, V any](t *testing.T, m *Map[K, V]) {
+	t.Helper()
+	n := 0
+	for e := m.kl.root.next; e != &m.kl.root; e = e.next {
+		if e.next.prev != e {
+			t.Fatalf("broken link after %v", e.key)
+		}
+		n++
+	}
+	if m.kl.root.next.prev != &m.kl.root || n != m.Len() {
+		t.Fatalf("list has %d linked elements, want %d", n, m.Len())
+	}
+}
+
+func BenchmarkSortFunc(b *testing.B) {
+	m := New[int, int]()
+	for i := range 1_000_000 {
+		m.Set(i*7919%1_000_003, i)
+	}
+	for b.Loop() {
+		SortFunc(m, func(k1, k2 int) int { return k2 - k1 })
+		SortFunc(m, cmp.Compare)
+	}
+}
+
+func BenchmarkSortParallel(b *testing.B) {
+	m := New[int, int]()
+	for i := range 1_000_000 {
+		m.Set(i*7919%1_000_003, i)
+	}
+	for b.Loop() {
+		SortParallel(m, func(k1, k2 int) int { return k2 - k1 })
+		SortParallel(m, cmp.Compare)
+	}
+}
@@ -0,0 +1,483 @@
+package omap
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+	"reflect"
+)
+
+const maxLevel = 32
+
+// skipNode is a node of the skip list backing a SortedMap.
+type skipNode[K comparable, V any] struct {
+	key  K
+	val  V
+	prev *skipNode[K, V]
+	next []skipLink[K, V]
+}
+
+// skipLink is a forward link of a skipNode. The span is the number of
+// level 0 steps the link covers, which allows rank queries in O(log n).
+type skipLink[K comparable, V any] struct {
+	node *skipNode[K, V]
+	span int
+}
+
+// SortedMap represents a map that keeps its keys sorted by a comparison function.
+// It is backed by an indexable skip list, so that Set, Get, Delete and rank
+// queries take O(log n).
+//
+// The zero value is ready to use for keys whose underlying type is ordered.
+// A SortedMap must not be copied after first use.
+type SortedMap[K comparable, V any] struct {
+	_       noCopy
+	compare func(a, b K) int
+	head    skipNode[K, V]
+	tail    *skipNode[K, V]
+	level   int
+	len     int
+	mod     uint
+}
+
+// NewSorted creates and returns a new SortedMap ordered by cmp.Compare.
+func NewSorted[K cmp.Ordered, V any]() *SortedMap[K, V] {
+	return NewSortedFunc[K, V](cmp.Compare[K])
+}
+
+// NewSortedFunc creates and returns a new SortedMap ordered by the given comparison function.
+// Keys comparing equal are considered the same key.
+func NewSortedFunc[K comparable, V any](compare func(a, b K) int) *SortedMap[K, V] {
+	s := &SortedMap[K, V]{compare: compare}
+	s.init()
+	return s
+}
+
+func (s *SortedMap[K, V]) lazyInit() {
+	if s.head.next == nil {
+		if s.compare == nil {
+			s.compare = orderedCompare[K]()
+		}
+		s.init()
+	}
+}
+
+func (s *SortedMap[K, V]) init() {
+	s.head.next = make([]skipLink[K, V], maxLevel)
+	s.tail = nil
+	s.level = 1
+	s.len = 0
+	s.mod++
+}
+
+// orderedCompare returns a comparison function for keys whose underlying type is ordered.
+// Built-in types use cmp.Compare directly; named types are compared through reflect.
+func orderedCompare[K comparable]() func(a, b K) int {
+	switch any(*new(K)).(type) {
+	case string:
+		return any(cmp.Compare[string]).(func(a, b K) int)
+	case int:
+		return any(cmp.Compare[int]).(func(a, b K) int)
+	case int8:
+		return any(cmp.Compare[int8]).(func(a, b K) int)
+	case int16:
+		return any(cmp.Compare[int16]).(func(a, b K) int)
+	case int32:
+		return any(cmp.Compare[int32]).(func(a, b K) int)
+	case int64:
+		return any(cmp.Compare[int64]).(func(a, b K) int)
+	case uint:
+		return any(cmp.Compare[uint]).(func(a, b K) int)
+	case uint8:
+		return any(cmp.Compare[uint8]).(func(a, b K) int)
+	case uint16:
+		return any(cmp.Compare[uint16]).(func(a, b K) int)
+	case uint32:
+		return any(cmp.Compare[uint32]).(func(a, b K) int)
+	case uint64:
+		return any(cmp.Compare[uint64]).(func(a, b K) int)
+	case uintptr:
+		return any(cmp.Compare[uintptr]).(func(a, b K) int)
+	case float32:
+		return any(cmp.Compare[float32]).(func(a, b K) int)
+	case float64:
+		return any(cmp.Compare[float64]).(func(a, b K) int)
+	}
+
+	t := reflect.TypeFor[K]()
+	switch t.Kind() {
+	case reflect.String:
+		return func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).String(), reflect.ValueOf(b).String())
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).Int(), reflect.ValueOf(b).Int())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).Uint(), reflect.ValueOf(b).Uint())
+		}
+	case reflect.Float32, reflect.Float64:
+		return func(a, b K) int {
+			return cmp.Compare(reflect.ValueOf(a).Float(), reflect.ValueOf(b).Float())
+		}
+	}
+	panic("omap: zero SortedMap requires an ordered key type, use NewSortedFunc for " + t.String())
+}
+
+func randomLevel() int {
+	// each level is promoted with a probability of 1/4
+	level := 1 + bits.TrailingZeros64(rand.Uint64()|1<<62)/2
+	return min(level, maxLevel)
+}
+
+// search returns the last node at each level whose key is less than key,
+// together with the rank of each of those nodes.
+func (s *SortedMap[K, V]) search(key K, update *[maxLevel]*skipNode[K, V], rank *[maxLevel]int) {
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		if i < s.level-1 {
+			rank[i] = rank[i+1]
+		} else {
+			rank[i] = 0
+		}
+		for x.next[i].node != nil && s.compare(x.next[i].node.key, key) < 0 {
+			rank[i] += x.next[i].span
+			x = x.next[i].node
+		}
+		update[i] = x
+	}
+}
+
+// seek returns the last node whose key is less than key, or less than or equal
+// to key if inclusive is set. It returns the head if there is no such node.
+func (s *SortedMap[K, V]) seek(key K, inclusive bool) *skipNode[K, V] {
+	x := &s.head
+	for i := s.level - 1; i >= 0; i-- {
+		for next := x.next[i].node; next != nil; next = x.next[i].node {
+			c := s.compare(next.key, key)
+			if c > 0 || c == 0 && !inclusive {
+				break
+			}
+			x = next
+		}
+	}
+	return x
+}
+
+// find returns the node of the key or nil.
+func (s *SortedMap[K, V]) find(key K) *skipNode[K, V] {
+	if s.len == 0 {
+		return nil
+	}
+	x := s.seek(key, false).next[0].node
+	if x != nil && s.compare(x.key, key) == 0 {
+		return x
+	}
+	return nil
+}
+
+// node returns n, or nil if n is the head.
+func (s *SortedMap[K, V]) node(n *skipNode[K, V]) *skipNode[K, V] {
+	if n == &s.head {
+		return nil
+	}
+	return n
+}
+
+// Set adds a key-value pair to the map at the position determined by its key.
+// If the key already exists, its value is updated.
+func (s *SortedMap[K, V]) Set(key K, value V) {
+	s.set(key, value, true)
+}
+
+// TrySet adds a key-value pair to the map only if the key does not already exist.
+// It returns true if the key-value pair was added, and false if the key already exists.
+func (s *SortedMap[K, V]) TrySet(key K, value V) bool {
+	return s.set(key, value, false)
+}
+
+func (s *SortedMap[K, V]) set(key K, value V, overwrite bool) bool {
+	s.lazyInit()
+
+	var update [maxLevel]*skipNode[K, V]
+	var rank [maxLevel]int
+	s.search(key, &update, &rank)
+
+	if x := update[0].next[0].node; x != nil && s.compare(x.key, key) == 0 {
+		if overwrite {
+			x.val = value
+		}
+		return false
+	}
+
+	level := randomLevel()
+	if level > s.level {
+		for i := s.level; i < level; i++ {
+			rank[i] = 0
+			update[i] = &s.head
+			update[i].next[i].span = s.len
+		}
+		s.level = level
+	}
+
+	x := &skipNode[K, V]{key: key, val: value, next: make([]skipLink[K, V], level)}
+	for i := range level {
+		x.next[i].node = update[i].next[i].node
+		update[i].next[i].node = x
+		x.next[i].span = update[i].next[i].span - (rank[0] - rank[i])
+		update[i].next[i].span = rank[0] - rank[i] + 1
+	}
+	for i := level; i < s.level; i++ {
+		update[i].next[i].span++
+	}
+
+	x.prev = s.node(update[0])
+	if next := x.next[0].node; next != nil {
+		next.prev = x
+	} else {
+		s.tail = x
+	}
+	s.len++
+	s.mod++
+	return true
+}
+
+// Get retrieves the value associated with the given key.
+func (s *SortedMap[K, V]) Get(key K) (value V) {
+	value, _ = s.TryGet(key)
+	return
+}
+
+// TryGet retrieves the value associated with the given key.
+// It returns the value and true if the key exists, otherwise the zero value and false.
+func (s *SortedMap[K, V]) TryGet(key K) (value V, ok bool) {
+	if x := s.find(key); x != nil {
+		return x.val, true
+	}
+	return
+}
+
+// Has checks if the given key exists in the map.
+func (s *SortedMap[K, V]) Has(key K) bool {
+	return s.find(key) != nil
+}
+
+// Delete removes the key-value pairs associated with the given keys from the map.
+// It is no-op if a key does not exist.
+func (s *SortedMap[K, V]) Delete(keys ...K) {
+	if s.len == 0 {
+		return
+	}
+	var update [maxLevel]*skipNode[K, V]
+	var rank [maxLevel]int
+	for _, key := range keys {
+		s.search(key, &update, &rank)
+		if x := update[0].next[0].node; x != nil && s.compare(x.key, key) == 0 {
+			s.unlink(x, &update)
+		}
+	}
+}
+
+func (s *SortedMap[K, V]) unlink(x *skipNode[K, V], update *[maxLevel]*skipNode[K, V]) {
+	for i := range s.level {
+		if update[i].next[i].node == x {
+			update[i].next[i].span += x.next[i].span - 1
+			update[i].next[i].node = x.next[i].node
+		} else {
+			update[i].next[i].span--
+		}
+	}
+	if next := x.next[0].node; next != nil {
+		next.prev = x.prev
+	} else {
+		s.tail = x.prev
+	}
+	for s.level > 1 && s.head.next[s.level-1].node == nil {
+		s.level--
+	}
+	s.len--
+	s.mod++
+}
+
+// Clear removes all key-value pairs from the map.
+func (s *SortedMap[K, V]) Clear() {
+	if s.head.next != nil {
+		s.init()
+	}
+}
+
+// Len returns the number of key-value pairs in the map.
+func (s *SortedMap[K, V]) Len() int {
+	return s.len
+}
+
+// walk calls yield for each node starting at x in the given direction until yield
+// returns false. If the map is modified while yield runs, the walk continues with
+// the node following the last yielded key.
+func (s *SortedMap[K, V]) walk(x *skipNode[K, V], backward bool, yield func(x *skipNode[K, V]) bool) {
+	mod := s.mod
+	for x != nil {
+		key := x.key
+		if !yield(x) {
+			return
+		}
+		switch {
+		case s.mod != mod:
+			mod = s.mod
+			if backward {
+				x = s.node(s.seek(key, false))
+			} else {
+				x = s.seek(key, true).next[0].node
+			}
+		case backward:
+			x = x.prev
+		default:
+			x = x.next[0].node
+		}
+	}
+}
+
+func (s *SortedMap[K, V]) front() *skipNode[K, V] {
+	if s.len == 0 {
+		return nil
+	}
+	return s.head.next[0].node
+}
+
+// All returns an iterator over the map's entries in key order.
+// The map may be modified during iteration; the iteration then continues with
+// the smallest key greater than the last produced key.
+func (s *SortedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.walk(s.front(), false, func(x *skipNode[K, V]) bool {
+			return yield(x.key, x.val)
+		})
+	}
+}
+
+// Backward returns an iterator over the map's entries in reverse key order.
+func (s *SortedMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		s.walk(s.tail, true, func(x *skipNode[K, V]) bool {
+			return yield(x.key, x.val)
+		})
+	}
+}
+
+// Range returns an iterator over the entries whose keys are in the range [lo, hi), in key order.
+func (s *SortedMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if s.len == 0 {
+			return
+		}
+		s.walk(s.seek(lo, false).next[0].node, false, func(x *skipNode[K, V]) bool {
+			return s.compare(x.key, hi) < 0 && yield(x.key, x.val)
+		})
+	}
+}
+
+// Keys returns a slice of all keys in the map, in key order.
+func (s *SortedMap[K, V]) Keys() []K {
+	keys := make([]K, 0, s.len)
+	for x := s.front(); x != nil; x = x.next[0].node {
+		keys = append(keys, x.key)
+	}
+	return keys
+}
+
+// Values returns a slice of all values in the map, in the order of their keys.
+func (s *SortedMap[K, V]) Values() []V {
+	values := make([]V, 0, s.len)
+	for x := s.front(); x != nil; x = x.next[0].node {
+		values = append(values, x.val)
+	}
+	return values
+}
+
+func entryOf[K comparable, V any](x *skipNode[K, V]) (key K, value V, ok bool) {
+	if x == nil {
+		return key, value, false
+	}
+	return x.key, x.val, true
+}
+
+// Min returns the entry with the smallest key.
+// The ok result is false if the map is empty.
+func (s *SortedMap[K, V]) Min() (key K, value V, ok bool) {
+	return entryOf(s.front())
+}
+
+// Max returns the entry with the largest key.
+// The ok result is false if the map is empty.
+func (s *SortedMap[K, V]) Max() (key K, value V, ok bool) {
+	return entryOf(s.tail)
+}
+
+// Floor returns the entry with the largest key less than or equal to the given key.
+// The ok result is false if there is no such entry.
+func (s *SortedMap[K, V]) Floor(key K) (K, V, bool) {
+	if s.len == 0 {
+		return entryOf[K, V](nil)
+	}
+	return entryOf(s.node(s.seek(key, true)))
+}
+
+// Lower returns the entry with the largest key strictly less than the given key.
+// The ok result is false if there is no such entry.
+func (s *SortedMap[K, V]) Lower(key K) (K, V, bool) {
+	if s.len == 0 {
+		return entryOf[K, V](nil)
+	}
+	return entryOf(s.node(s.seek(key, false)))
+}
+
+// Ceiling returns the entry with the smallest key greater than or equal to the given key.
+// The ok result is false if there is no such entry.
+func (s *SortedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	if s.len == 0 {
+		return entryOf[K, V](nil)
+	}
+	return entryOf(s.seek(key, false).next[0].node)
+}
+
+// Higher returns the entry with the smallest key strictly greater than the given key.
+// The ok result is false if there is no such entry.
+func (s *SortedMap[K, V]) Higher(key K) (K, V, bool) {
+	if s.len == 0 {
+		return entryOf[K, V](nil)
+	}
+	return entryOf(s.seek(key, true).next[0].node)
+}
+
+// Rank returns the number of keys strictly less than the given key in O(log n).
+// For an existing key, this is its position in the map.
+func (s *SortedMap[K, V]) Rank(key K) int {
+	if s.len == 0 {
+		return 0
+	}
+	var update [maxLevel]*skipNode[K, V]
+	var rank [maxLevel]int
+	s.search(key, &update, &rank)
+	return rank[0]
+}
+
+// At returns the key-value pair at position i in key order in O(log n).
+// It panics if i is out of range.
+func (s *SortedMap[K, V]) At(i int) (K, V) {
+	checkIndex(i, s.len)
+	x := &s.head
+	traversed := 0
+	for l := s.level - 1; l >= 0; l-- {
+		for x.next[l].node != nil && traversed+x.next[l].span <= i+1 {
+			traversed += x.next[l].span
+			x = x.next[l].node
+		}
+		if traversed == i+1 {
+			break
+		}
+	}
+	return x.key, x.val
+}
@@ -0,0 +1,286 @@
+package omap
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSortedMap_Set(t *testing.T) {
+	s := NewSorted[string, int]()
+	for i, k := range []string{"c", "a", "d", "b"} {
+		s.Set(k, i)
+	}
+	s.Set("a", 10)
+
+	if want := []string{"a", "b", "c", "d"}; !slices.Equal(s.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", s.Keys(), want)
+	}
+	if want := []int{10, 3, 0, 2}; !slices.Equal(s.Values(), want) {
+		t.Errorf("Values() = %v, want %v", s.Values(), want)
+	}
+	if s.Len() != 4 {
+		t.Errorf("Len() = %d, want 4", s.Len())
+	}
+
+	if s.TrySet("a", 20) {
+		t.Error("TrySet(a) should fail on existing key")
+	}
+	if !s.TrySet("e", 5) || s.Get("e") != 5 {
+		t.Error("TrySet(e) should add the key")
+	}
+}
+
+func TestSortedMap_Zero(t *testing.T) {
+	type T string
+	var s SortedMap[T, int]
+
+	if _, ok := s.TryGet("a"); ok {
+		t.Error("TryGet() on zero map should fail")
+	}
+	if _, _, ok := s.Min(); ok {
+		t.Error("Min() on zero map should fail")
+	}
+	for range s.All() {
+		t.Error("All() on zero map should be empty")
+	}
+	s.Delete("a")
+
+	s.Set("b", 2)
+	s.Set("a", 1)
+	if want := []T{"a", "b"}; !slices.Equal(s.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", s.Keys(), want)
+	}
+
+	var f SortedMap[float64, int]
+	f.Set(2.5, 0)
+	f.Set(-1, 0)
+	if want := []float64{-1, 2.5}; !slices.Equal(f.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", f.Keys(), want)
+	}
+
+	// built-in key types are compared with cmp.Compare, without boxing the keys
+	var z SortedMap[string, int]
+	z.Set("a", 1)
+	z.Set("c", 3)
+	if n := testing.AllocsPerRun(100, func() { z.Get("b") }); n != 0 {
+		t.Errorf("Get() on zero map with string keys allocates %v times", n)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("zero map with unordered key type should panic")
+		}
+	}()
+	var p SortedMap[struct{ x int }, int]
+	p.Set(struct{ x int }{1}, 0)
+}
+
+func TestSortedMap_Func(t *testing.T) {
+	s := NewSortedFunc[string, int](func(a, b string) int {
+		return cmp.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	s.Set("b", 1)
+	s.Set("A", 2)
+	s.Set("a", 3)
+
+	if want := []string{"A", "b"}; !slices.Equal(s.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", s.Keys(), want)
+	}
+	if s.Get("a") != 3 {
+		t.Errorf("Get(a) = %v, want 3", s.Get("a"))
+	}
+}
+
+func TestSortedMap_Delete(t *testing.T) {
+	s := NewSorted[int, int]()
+	for i := range 10 {
+		s.Set(i, i)
+	}
+
+	s.Delete(0, 5, 9, 42)
+	if want := []int{1, 2, 3, 4, 6, 7, 8}; !slices.Equal(s.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", s.Keys(), want)
+	}
+	if s.Has(5) {
+		t.Error("Has(5) after Delete = true, want false")
+	}
+	if k, _, _ := s.Max(); k != 8 {
+		t.Errorf("Max() = %v, want 8", k)
+	}
+
+	s.Clear()
+	if s.Len() != 0 || len(s.Keys()) != 0 {
+		t.Errorf("Len() after Clear = %d", s.Len())
+	}
+}
+
+func TestSortedMap_Navigation(t *testing.T) {
+	s := NewSorted[int, string]()
+	for _, k := range []int{10, 20, 30} {
+		s.Set(k, "")
+	}
+
+	tests := []struct {
+		name string
+		fn   func(int) (int, string, bool)
+		key  int
+		want int
+		ok   bool
+	}{
+		{"Floor exact", s.Floor, 20, 20, true},
+		{"Floor between", s.Floor, 25, 20, true},
+		{"Floor below", s.Floor, 5, 0, false},
+		{"Lower exact", s.Lower, 20, 10, true},
+		{"Lower below", s.Lower, 10, 0, false},
+		{"Ceiling exact", s.Ceiling, 20, 20, true},
+		{"Ceiling between", s.Ceiling, 15, 20, true},
+		{"Ceiling above", s.Ceiling, 35, 0, false},
+		{"Higher exact", s.Higher, 20, 30, true},
+		{"Higher above", s.Higher, 30, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			k, _, ok := tt.fn(tt.key)
+			if k != tt.want || ok != tt.ok {
+				t.Errorf("got (%v, %v), want (%v, %v)", k, ok, tt.want, tt.ok)
+			}
+		})
+	}
+
+	if k, _, ok := s.Min(); k != 10 || !ok {
+		t.Errorf("Min() = (%v, %v), want (10, true)", k, ok)
+	}
+	if k, _, ok := s.Max(); k != 30 || !ok {
+		t.Errorf("Max() = (%v, %v), want (30, true)", k, ok)
+	}
+}
+
+func TestSortedMap_Range(t *testing.T) {
+	s := NewSorted[int, int]()
+	for i := 0; i < 100; i += 10 {
+		s.Set(i, i)
+	}
+
+	if got, want := collectKeys(s.Range(15, 50)), []int{20, 30, 40}; !slices.Equal(got, want) {
+		t.Errorf("Range(15, 50) = %v, want %v", got, want)
+	}
+	if got := collectKeys(s.Range(50, 50)); len(got) != 0 {
+		t.Errorf("Range(50, 50) = %v, want empty", got)
+	}
+	if got, want := collectKeys(s.Backward()), []int{90, 80, 70, 60, 50, 40, 30, 20, 10, 0}; !slices.Equal(got, want) {
+		t.Errorf("Backward() = %v, want %v", got, want)
+	}
+}
+
+func TestSortedMap_Modify(t *testing.T) {
+	s := NewSorted[int, int]()
+	for i := range 10 {
+		s.Set(i, i)
+	}
+
+	var seen []int
+	for k := range s.All() {
+		seen = append(seen, k)
+		switch k {
+		case 2:
+			s.Delete(3, 4)
+		case 5:
+			s.Set(7, 70)
+			s.Set(6, 60)
+			s.Delete(5)
+		case 8:
+			s.Clear()
+		}
+	}
+	if want := []int{0, 1, 2, 5, 6, 7, 8}; !slices.Equal(seen, want) {
+		t.Errorf("seen = %v, want %v", seen, want)
+	}
+
+	for i := range 5 {
+		s.Set(i, i)
+	}
+	seen = seen[:0]
+	for k := range s.Backward() {
+		seen = append(seen, k)
+		if k == 3 {
+			s.Delete(2)
+		}
+	}
+	if want := []int{4, 3, 1, 0}; !slices.Equal(seen, want) {
+		t.Errorf("seen backward = %v, want %v", seen, want)
+	}
+}
+
+func TestSortedMap_Rank(t *testing.T) {
+	s := NewSorted[int, int]()
+	for i := 0; i < 10; i++ {
+		s.Set(i*10, i)
+	}
+
+	if got := s.Rank(30); got != 3 {
+		t.Errorf("Rank(30) = %d, want 3", got)
+	}
+	if got := s.Rank(35); got != 4 {
+		t.Errorf("Rank(35) = %d, want 4", got)
+	}
+	if got := s.Rank(-1); got != 0 {
+		t.Errorf("Rank(-1) = %d, want 0", got)
+	}
+	if k, v := s.At(7); k != 70 || v != 7 {
+		t.Errorf("At(7) = (%v, %v), want (70, 7)", k, v)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("At(10) should panic")
+		}
+	}()
+	s.At(10)
+}
+
+func TestSortedMap_Randomized(t *testing.T) {
+	r := rand.New(rand.NewPCG(3, 4))
+	s := NewSorted[int, int]()
+	model := map[int]int{}
+
+	for i := range 20000 {
+		k := r.IntN(2000)
+		switch r.IntN(3) {
+		case 0, 1:
+			s.Set(k, i)
+			model[k] = i
+		default:
+			s.Delete(k)
+			delete(model, k)
+		}
+	}
+
+	keys := make([]int, 0, len(model))
+	for k := range model {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+
+	if !slices.Equal(s.Keys(), keys) {
+		t.Fatalf("Keys() diverged from model")
+	}
+	for i, k := range keys {
+		if s.Get(k) != model[k] {
+			t.Fatalf("Get(%d) = %d, want %d", k, s.Get(k), model[k])
+		}
+		if got := s.Rank(k); got != i {
+			t.Fatalf("Rank(%d) = %d, want %d", k, got, i)
+		}
+		if got, _ := s.At(i); got != k {
+			t.Fatalf("At(%d) = %d, want %d", i, got, k)
+		}
+	}
+	slices.Reverse(keys)
+	if got := collectKeys(s.Backward()); !slices.Equal(got, keys) {
+		t.Fatalf("Backward() diverged from model")
+	}
+}
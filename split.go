@@ -0,0 +1,105 @@
+package omap
+
+// SplitAt splits the map into the entries before key and the entries from key onward.
+// If key does not exist, all entries end up in the first map.
+//
+// The entries are moved rather than copied: m is reused for the larger part and a new
+// map is created for the smaller one, so the cost is proportional to the smaller part.
+// m must only be used through the returned maps afterwards.
+func (m *Map[K, V]) SplitAt(key K) (*Map[K, V], *Map[K, V]) {
+	e, ok := m.kv[key]
+	if !ok {
+		m.lazyInit()
+		return m, New[K, V]()
+	}
+
+	// count the entries before e, walking from both ends of the list
+	// so that only the smaller part is visited
+	front, back := e.prev, e
+	for n := 0; ; n++ {
+		if front == &m.kl.root {
+			return m.split(e, n)
+		}
+		if back == &m.kl.root {
+			return m.split(e, m.Len()-n)
+		}
+		front, back = front.prev, back.next
+	}
+}
+
+// SplitAtIndex splits the map into the entries in positions [0, i) and [i, Len()).
+// Like SplitAt, it moves the entries of the smaller part into a new map and reuses m
+// for the larger one. It panics if i is not in the range [0, Len()].
+func (m *Map[K, V]) SplitAtIndex(i int) (*Map[K, V], *Map[K, V]) {
+	n := m.Len()
+	checkIndex(i, n+1)
+	m.lazyInit()
+
+	e := m.kl.root.next
+	if i <= n-i {
+		for range i {
+			e = e.next
+		}
+	} else {
+		e = &m.kl.root
+		for range n - i {
+			e = e.prev
+		}
+	}
+	return m.split(e, i)
+}
+
+// split cuts the map right before at, which is preceded by n entries.
+func (m *Map[K, V]) split(at *Element[K, V], n int) (*Map[K, V], *Map[K, V]) {
+	if n <= m.Len()-n {
+		return m.cut(m.kl.root.next, at.prev, n), m
+	}
+	return m, m.cut(at, m.kl.root.prev, m.Len()-n)
+}
+
+// cut moves the n entries from first through last into a new map.
+func (m *Map[K, V]) cut(first, last *Element[K, V], n int) *Map[K, V] {
+	part := Make[K, V](n)
+	if n == 0 {
+		return part
+	}
+	m.kl.splice(first, last, n, &part.kl)
+	for e := first; e != &part.kl.root; e = e.next {
+		delete(m.kv, e.key)
+		part.kv[e.key] = e
+	}
+	return part
+}
+
+// Concat moves the entries of other to the end of m, keeping their order, and leaves
+// other empty. It returns false and changes nothing if the maps share a key.
+//
+// The entries are relinked rather than copied, and the order-statistics indexes of
+// both maps are joined in O(log n) when present.
+func (m *Map[K, V]) Concat(other *Map[K, V]) bool {
+	if other == m {
+		return m.Len() == 0
+	}
+	if other.Len() == 0 {
+		return true
+	}
+
+	small, large := m.kv, other.kv
+	if len(small) > len(large) {
+		small, large = large, small
+	}
+	for k := range small {
+		if _, ok := large[k]; ok {
+			return false
+		}
+	}
+
+	m.lazyInit()
+	first := other.kl.root.next
+	other.kl.splice(first, other.kl.root.prev, other.Len(), &m.kl)
+	for e := first; e != &m.kl.root; e = e.next {
+		m.kv[e.key] = e
+	}
+	other.init(0)
+	return true
+}
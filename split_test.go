@@ -0,0 +1,139 @@
+package omap
+
+import (
+	"slices"
+	"testing"
+)
+
+// checkMap verifies that the keys, the links and the index of m agree with want.
+func checkMap(t *testing.T, m *Map[int, int], want []int) {
+	t.Helper()
+	if got := m.Keys(); !slices.Equal(got, want) {
+		t.Fatalf("Keys() = %v, want %v", got, want)
+	}
+	checkLinks(t, m)
+	for i, k := range want {
+		if m.Get(k) != k {
+			t.Fatalf("Get(%d) = %d", k, m.Get(k))
+		}
+		if got := m.IndexOf(k); got != i {
+			t.Fatalf("IndexOf(%d) = %d, want %d", k, got, i)
+		}
+		if e := m.GetElement(k); e.list != &m.kl {
+			t.Fatalf("element %d belongs to another list", k)
+		}
+	}
+}
+
+func TestMap_SplitAtIndex(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		for i := range 11 {
+			m := newRange(10)
+			if indexed {
+				m.At(0)
+			}
+			a, b := m.SplitAtIndex(i)
+			checkMap(t, a, slices.Collect(func(yield func(int) bool) {
+				for k := range i {
+					yield(k)
+				}
+			}))
+			checkMap(t, b, slices.Collect(func(yield func(int) bool) {
+				for k := i; k < 10; k++ {
+					yield(k)
+				}
+			}))
+			if a != m && b != m {
+				t.Errorf("SplitAtIndex(%d) did not reuse the map", i)
+			}
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("SplitAtIndex(11) did not panic")
+		}
+	}()
+	newRange(10).SplitAtIndex(11)
+}
+
+func TestMap_SplitAt(t *testing.T) {
+	a, b := newRange(5).SplitAt(3)
+	checkMap(t, a, []int{0, 1, 2})
+	checkMap(t, b, []int{3, 4})
+
+	a, b = newRange(5).SplitAt(1)
+	checkMap(t, a, []int{0})
+	checkMap(t, b, []int{1, 2, 3, 4})
+
+	a, b = newRange(5).SplitAt(9)
+	checkMap(t, a, []int{0, 1, 2, 3, 4})
+	checkMap(t, b, nil)
+
+	a, b = New[int, int]().SplitAt(0)
+	checkMap(t, a, nil)
+	checkMap(t, b, nil)
+
+	// both parts stay usable
+	a, b = newRange(5).SplitAt(2)
+	a.Set(10, 10)
+	b.Set(0, 0)
+	b.MoveToFront(4)
+	checkMap(t, a, []int{0, 1, 10})
+	checkMap(t, b, []int{4, 2, 3, 0})
+}
+
+func TestMap_Concat(t *testing.T) {
+	for _, indexed := range []bool{false, true} {
+		a, b := newRange(5).SplitAt(2)
+		if indexed {
+			a.At(0)
+		}
+		if !a.Concat(b) {
+			t.Fatal("Concat() = false")
+		}
+		checkMap(t, a, []int{0, 1, 2, 3, 4})
+		checkMap(t, b, nil)
+
+		// the emptied map is reusable
+		b.Set(7, 7)
+		checkMap(t, b, []int{7})
+		if !b.Concat(a) {
+			t.Fatal("Concat() = false")
+		}
+		checkMap(t, b, []int{7, 0, 1, 2, 3, 4})
+	}
+
+	a, b := newRange(3), newRange(5)
+	b.KeepLast(3)
+	if a.Concat(b) {
+		t.Error("Concat() with overlapping keys = true")
+	}
+	checkMap(t, a, []int{0, 1, 2})
+	checkMap(t, b, []int{2, 3, 4})
+
+	if a.Concat(a) {
+		t.Error("Concat() with itself = true")
+	}
+	if !a.Concat(New[int, int]()) {
+		t.Error("Concat() with an empty map = false")
+	}
+	var empty Map[int, int]
+	if !empty.Concat(a) {
+		t.Error("Concat() into a zero map = false")
+	}
+	checkMap(t, &empty, []int{0, 1, 2})
+}
+
+func TestMap_Concat_ModifyDuringIteration(t *testing.T) {
+	a, b := newRange(2), New[int, int]()
+	b.Set(5, 5)
+	defer func() {
+		if recover() == nil {
+			t.Error("Concat() during iteration did not panic")
+		}
+	}()
+	for range a.All() {
+		a.Concat(b)
+	}
+}
@@ -0,0 +1,485 @@
+package omap
+
+import (
+	"hash/maphash"
+	"iter"
+	"sync"
+)
+
+// SyncMap is an ordered map that is safe for concurrent use by multiple goroutines.
+// It wraps a Map with a read-write mutex and offers the same methods, except for
+// element handles and splitting, which would expose the entries outside of the lock.
+//
+// Iterators walk a snapshot of the entries that is taken when the iteration starts,
+// so the lock is not held while the loop body runs, and the loop body may freely
+// modify the map. Functions passed to methods such as Compute or DeleteFunc run while
+// the lock is held; they make the method atomic, but must not call methods of the map.
+//
+// The zero value is an empty map ready to use. A SyncMap must not be copied after first use.
+type SyncMap[K comparable, V any] struct {
+	mu sync.RWMutex
+	m  Map[K, V]
+}
+
+// NewSync creates and returns a new SyncMap instance.
+func NewSync[K comparable, V any]() *SyncMap[K, V] {
+	return MakeSync[K, V](0)
+}
+
+// MakeSync creates and returns a new SyncMap instance with the specified capacity.
+func MakeSync[K comparable, V any](capacity int) *SyncMap[K, V] {
+	s := &SyncMap[K, V]{}
+	s.m.init(capacity)
+	return s
+}
+
+func (s *SyncMap[K, V]) read() func() {
+	s.mu.RLock()
+	return s.mu.RUnlock
+}
+
+func (s *SyncMap[K, V]) write() func() {
+	s.mu.Lock()
+	return s.mu.Unlock
+}
+
+// Set adds a key-value pair to the map, or updates the value of an existing key.
+func (s *SyncMap[K, V]) Set(key K, value V) {
+	defer s.write()()
+	s.m.Set(key, value)
+}
+
+// TrySet adds a key-value pair to the map only if the key does not already exist.
+// It returns true if the key-value pair was added.
+func (s *SyncMap[K, V]) TrySet(key K, value V) bool {
+	defer s.write()()
+	return s.m.TrySet(key, value)
+}
+
+// Get retrieves the value associated with the given key.
+func (s *SyncMap[K, V]) Get(key K) V {
+	defer s.read()()
+	return s.m.Get(key)
+}
+
+// TryGet retrieves the value associated with the given key.
+// It returns the value and true if the key exists, otherwise the zero value and false.
+func (s *SyncMap[K, V]) TryGet(key K) (V, bool) {
+	defer s.read()()
+	return s.m.TryGet(key)
+}
+
+// Delete removes the key-value pairs with the given keys.
+func (s *SyncMap[K, V]) Delete(keys ...K) {
+	defer s.write()()
+	s.m.Delete(keys...)
+}
+
+// Has checks if the map contains the given key.
+func (s *SyncMap[K, V]) Has(key K) bool {
+	defer s.read()()
+	return s.m.Has(key)
+}
+
+// Clear removes all key-value pairs from the map.
+func (s *SyncMap[K, V]) Clear() {
+	defer s.write()()
+	s.m.Clear()
+}
+
+// Len returns the number of key-value pairs in the map.
+func (s *SyncMap[K, V]) Len() int {
+	defer s.read()()
+	return s.m.Len()
+}
+
+// Keys returns a slice of all keys in the map, in insertion order.
+func (s *SyncMap[K, V]) Keys() []K {
+	defer s.read()()
+	return s.m.Keys()
+}
+
+// Values returns a slice of all values in the map, in the order of their keys.
+func (s *SyncMap[K, V]) Values() []V {
+	defer s.read()()
+	return s.m.Values()
+}
+
+// Compute atomically updates the value of the key, as Map.Compute does.
+func (s *SyncMap[K, V]) Compute(key K, fn func(old V, exists bool) (V, bool)) (V, bool) {
+	defer s.write()()
+	return s.m.Compute(key, fn)
+}
+
+// ComputeIfAbsent atomically sets the key to the result of fn if it does not exist,
+// as Map.ComputeIfAbsent does.
+func (s *SyncMap[K, V]) ComputeIfAbsent(key K, fn func() V) V {
+	defer s.write()()
+	return s.m.ComputeIfAbsent(key, fn)
+}
+
+// ComputeIfPresent atomically updates the value of an existing key, as Map.ComputeIfPresent does.
+func (s *SyncMap[K, V]) ComputeIfPresent(key K, fn func(old V) (V, bool)) (V, bool) {
+	defer s.write()()
+	return s.m.ComputeIfPresent(key, fn)
+}
+
+// GetOrSet atomically returns the existing value of the key, or sets and returns the given value.
+// The loaded result is true if the value was loaded.
+func (s *SyncMap[K, V]) GetOrSet(key K, value V) (actual V, loaded bool) {
+	defer s.write()()
+	return s.m.GetOrSet(key, value)
+}
+
+// SwapValue atomically stores the value and returns the previous value, if any.
+func (s *SyncMap[K, V]) SwapValue(key K, value V) (previous V, loaded bool) {
+	defer s.write()()
+	return s.m.SwapValue(key, value)
+}
+
+// LoadAndDelete atomically deletes the key and returns its value, if any.
+func (s *SyncMap[K, V]) LoadAndDelete(key K) (value V, loaded bool) {
+	defer s.write()()
+	return s.m.LoadAndDelete(key)
+}
+
+// DeleteFunc removes all entries for which del returns true, and returns the number of removed entries.
+func (s *SyncMap[K, V]) DeleteFunc(del func(K, V) bool) int {
+	defer s.write()()
+	return s.m.DeleteFunc(del)
+}
+
+// Retain keeps only the entries for which keep returns true, and returns the number of removed entries.
+func (s *SyncMap[K, V]) Retain(keep func(K, V) bool) int {
+	defer s.write()()
+	return s.m.Retain(keep)
+}
+
+// DeleteRange removes the entries from the key from through the key to, inclusive,
+// and returns the number of removed entries.
+func (s *SyncMap[K, V]) DeleteRange(from, to K) int {
+	defer s.write()()
+	return s.m.DeleteRange(from, to)
+}
+
+// Truncate keeps the first n entries, removes the rest and returns the number of removed entries.
+func (s *SyncMap[K, V]) Truncate(n int) int {
+	defer s.write()()
+	return s.m.Truncate(n)
+}
+
+// KeepLast keeps the last n entries, removes the rest and returns the number of removed entries.
+func (s *SyncMap[K, V]) KeepLast(n int) int {
+	defer s.write()()
+	return s.m.KeepLast(n)
+}
+
+// InsertBefore inserts a key-value pair right before mark.
+// It returns false if mark does not exist or key already exists.
+func (s *SyncMap[K, V]) InsertBefore(mark K, key K, value V) bool {
+	defer s.write()()
+	return s.m.InsertBefore(mark, key, value)
+}
+
+// InsertAfter inserts a key-value pair right after mark.
+// It returns false if mark does not exist or key already exists.
+func (s *SyncMap[K, V]) InsertAfter(mark K, key K, value V) bool {
+	defer s.write()()
+	return s.m.InsertAfter(mark, key, value)
+}
+
+// MoveToFront moves the key to the front. It returns false if the key does not exist.
+func (s *SyncMap[K, V]) MoveToFront(key K) bool {
+	defer s.write()()
+	return s.m.MoveToFront(key)
+}
+
+// MoveToBack moves the key to the back. It returns false if the key does not exist.
+func (s *SyncMap[K, V]) MoveToBack(key K) bool {
+	defer s.write()()
+	return s.m.MoveToBack(key)
+}
+
+// MoveBefore moves the key right before mark. It returns false if either key does not exist.
+func (s *SyncMap[K, V]) MoveBefore(key K, mark K) bool {
+	defer s.write()()
+	return s.m.MoveBefore(key, mark)
+}
+
+// MoveAfter moves the key right after mark. It returns false if either key does not exist.
+func (s *SyncMap[K, V]) MoveAfter(key K, mark K) bool {
+	defer s.write()()
+	return s.m.MoveAfter(key, mark)
+}
+
+// Swap exchanges the positions of two keys. It returns false if either key does not exist.
+func (s *SyncMap[K, V]) Swap(k1, k2 K) bool {
+	defer s.write()()
+	return s.m.Swap(k1, k2)
+}
+
+// Rename changes a key while keeping its value and position.
+// It returns false if the old key does not exist or the new key already exists.
+func (s *SyncMap[K, V]) Rename(oldKey, newKey K) bool {
+	defer s.write()()
+	return s.m.Rename(oldKey, newKey)
+}
+
+// Reverse reverses the order of the entries.
+func (s *SyncMap[K, V]) Reverse() {
+	defer s.write()()
+	s.m.Reverse()
+}
+
+// At returns the key-value pair at position i. It panics if i is out of range.
+// Like IndexOf and Slice, it takes the write lock, since it may build the index.
+func (s *SyncMap[K, V]) At(i int) (K, V) {
+	defer s.write()()
+	return s.m.At(i)
+}
+
+// IndexOf returns the position of the key, or -1 if the key does not exist.
+func (s *SyncMap[K, V]) IndexOf(key K) int {
+	defer s.write()()
+	return s.m.IndexOf(key)
+}
+
+// InsertAt inserts a key-value pair at position i. It returns false if the key already exists.
+// It panics if i is not in the range [0, Len()].
+func (s *SyncMap[K, V]) InsertAt(i int, key K, value V) bool {
+	defer s.write()()
+	return s.m.InsertAt(i, key, value)
+}
+
+// DeleteAt removes the key-value pair at position i and returns it. It panics if i is out of range.
+func (s *SyncMap[K, V]) DeleteAt(i int) (K, V) {
+	defer s.write()()
+	return s.m.DeleteAt(i)
+}
+
+// First returns the first key-value pair of the map.
+// The ok result is false if the map is empty.
+func (s *SyncMap[K, V]) First() (key K, value V, ok bool) {
+	defer s.read()()
+	return s.m.First()
+}
+
+// Last returns the last key-value pair of the map.
+// The ok result is false if the map is empty.
+func (s *SyncMap[K, V]) Last() (key K, value V, ok bool) {
+	defer s.read()()
+	return s.m.Last()
+}
+
+// PopFirst removes and returns the first key-value pair of the map.
+// The ok result is false if the map is empty.
+func (s *SyncMap[K, V]) PopFirst() (key K, value V, ok bool) {
+	defer s.write()()
+	return s.m.PopFirst()
+}
+
+// PopLast removes and returns the last key-value pair of the map.
+// The ok result is false if the map is empty.
+func (s *SyncMap[K, V]) PopLast() (key K, value V, ok bool) {
+	defer s.write()()
+	return s.m.PopLast()
+}
+
+// PopFirstN removes and returns up to n key-value pairs from the front of the map.
+func (s *SyncMap[K, V]) PopFirstN(n int) []Entry[K, V] {
+	defer s.write()()
+	return s.m.PopFirstN(n)
+}
+
+// Merge merges the key-value pairs from the target maps into the current map.
+func (s *SyncMap[K, V]) Merge(target ...OrderedMap[K, V]) {
+	s.MergeFunc(nil, MergeOptions{}, target...)
+}
+
+// MergeFunc merges the key-value pairs from the target maps into the current map,
+// as Map.MergeFunc does. The targets are read before the lock is taken, so maps
+// may be merged into each other concurrently without deadlocks.
+func (s *SyncMap[K, V]) MergeFunc(resolve func(key K, old, new V) V, opts MergeOptions, target ...OrderedMap[K, V]) {
+	sources := make([]OrderedMap[K, V], len(target))
+	for i, t := range target {
+		c := New[K, V]()
+		for k, v := range t.All() {
+			c.Set(k, v)
+		}
+		sources[i] = c
+	}
+	defer s.write()()
+	s.m.MergeFunc(resolve, opts, sources...)
+}
+
+// Snapshot returns a copy of the current entries as a Map.
+func (s *SyncMap[K, V]) Snapshot() *Map[K, V] {
+	return s.snapshotFunc(func(v V) V { return v })
+}
+
+// Clone returns a shallow copy of the map with the same order.
+func (s *SyncMap[K, V]) Clone() *SyncMap[K, V] {
+	return s.CloneFunc(func(v V) V { return v })
+}
+
+// CloneFunc returns a copy of the map with the same order, copying each value with clone.
+func (s *SyncMap[K, V]) CloneFunc(clone func(V) V) *SyncMap[K, V] {
+	c := &SyncMap[K, V]{}
+	c.m.Concat(s.snapshotFunc(clone))
+	return c
+}
+
+func (s *SyncMap[K, V]) snapshotFunc(clone func(V) V) *Map[K, V] {
+	defer s.read()()
+	return s.m.CloneFunc(clone)
+}
+
+// Equal reports whether both maps have the same entries in the same order.
+// The maps are never locked at the same time.
+func (s *SyncMap[K, V]) Equal(other *SyncMap[K, V]) bool {
+	return s == other || s.Snapshot().Equal(other.Snapshot())
+}
+
+// EqualFunc reports whether both maps have the same keys in the same order,
+// comparing values with eq.
+func (s *SyncMap[K, V]) EqualFunc(other *SyncMap[K, V], eq func(V, V) bool) bool {
+	return s.Snapshot().EqualFunc(other.Snapshot(), eq)
+}
+
+// EqualUnordered reports whether both maps have the same entries, in any order.
+func (s *SyncMap[K, V]) EqualUnordered(other *SyncMap[K, V]) bool {
+	return s == other || s.Snapshot().EqualUnordered(other.Snapshot())
+}
+
+// Hash returns an order-aware hash of the entries, consistent with Equal.
+func (s *SyncMap[K, V]) Hash(seed maphash.Seed) uint64 {
+	defer s.read()()
+	return s.m.Hash(seed)
+}
+
+// entries copies the entries from first through last, or through the end of the map
+// if last is nil, while holding the read lock. bounds looks up first and last.
+func (s *SyncMap[K, V]) entries(bounds func(m *Map[K, V]) (first, last *Element[K, V]), backward bool) []Entry[K, V] {
+	defer s.read()()
+	first, last := bounds(&s.m)
+	var entries []Entry[K, V]
+	for e := first; e != nil; {
+		entries = append(entries, Entry[K, V]{Key: e.key, Value: e.val})
+		switch {
+		case e == last:
+			e = nil
+		case backward:
+			e = e.Prev()
+		default:
+			e = e.Next()
+		}
+	}
+	return entries
+}
+
+func yieldEntries[K comparable, V any](entries []Entry[K, V], yield func(K, V) bool) {
+	for _, e := range entries {
+		if !yield(e.Key, e.Value) {
+			return
+		}
+	}
+}
+
+// All returns an iterator over a snapshot of the entries in insertion order.
+func (s *SyncMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		yieldEntries(s.entries(func(m *Map[K, V]) (first, last *Element[K, V]) {
+			return m.Front(), nil
+		}, false), yield)
+	}
+}
+
+// Backward returns an iterator over a snapshot of the entries in reverse insertion order.
+func (s *SyncMap[K, V]) Backward() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		yieldEntries(s.entries(func(m *Map[K, V]) (first, last *Element[K, V]) {
+			return m.Back(), nil
+		}, true), yield)
+	}
+}
+
+// KeysSeq returns an iterator over a snapshot of the keys in insertion order.
+func (s *SyncMap[K, V]) KeysSeq() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for k := range s.All() {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+// ValuesSeq returns an iterator over a snapshot of the values in insertion order.
+func (s *SyncMap[K, V]) ValuesSeq() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, v := range s.All() {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// Enumerate returns an iterator over a snapshot of the entries in insertion order,
+// paired with their positions.
+func (s *SyncMap[K, V]) Enumerate() iter.Seq2[int, Entry[K, V]] {
+	return func(yield func(int, Entry[K, V]) bool) {
+		entries := s.entries(func(m *Map[K, V]) (first, last *Element[K, V]) {
+			return m.Front(), nil
+		}, false)
+		for i, e := range entries {
+			if !yield(i, e) {
+				return
+			}
+		}
+	}
+}
+
+// From returns an iterator over a snapshot of the entries starting at the given key
+// through the end of the map. The iteration is empty if the key does not exist.
+func (s *SyncMap[K, V]) From(key K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		yieldEntries(s.entries(func(m *Map[K, V]) (first, last *Element[K, V]) {
+			return m.kv[key], nil
+		}, false), yield)
+	}
+}
+
+// Between returns an iterator over a snapshot of the entries from the key from through
+// the key to, inclusive, with the same rules as Map.Between.
+func (s *SyncMap[K, V]) Between(from, to K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		yieldEntries(s.entries(func(m *Map[K, V]) (first, last *Element[K, V]) {
+			first, last, _ = m.between(from, to)
+			return first, last
+		}, false), yield)
+	}
+}
+
+// Slice returns an iterator over a snapshot of the entries in positions [i, j).
+// It panics if the range is invalid.
+func (s *SyncMap[K, V]) Slice(i, j int) iter.Seq2[K, V] {
+	checkSlice(i, j, s.Len())
+	return func(yield func(K, V) bool) {
+		yieldEntries(s.slice(i, j), yield)
+	}
+}
+
+// slice copies the entries in positions [i, j) while holding the write lock,
+// since locating the first entry may build the index. Entries that were removed
+// since the range was checked are skipped.
+func (s *SyncMap[K, V]) slice(i, j int) []Entry[K, V] {
+	defer s.write()()
+	var entries []Entry[K, V]
+	if j = min(j, s.m.Len()); i < j {
+		for k, v := range s.m.Slice(i, j) {
+			entries = append(entries, Entry[K, V]{Key: k, Value: v})
+		}
+	}
+	return entries
+}
@@ -0,0 +1,200 @@
+package omap
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.yaml.in/yaml/v3"
+)
+
+func TestSyncMap(t *testing.T) {
+	var s SyncMap[string, int] // zero value is ready to use
+	s.Set("a", 1)
+	s.Set("b", 2)
+	if !s.TrySet("c", 3) || s.TrySet("a", 0) {
+		t.Error("TrySet() returned the wrong result")
+	}
+	if v, ok := s.TryGet("b"); !ok || v != 2 {
+		t.Errorf("TryGet(b) = %d, %v", v, ok)
+	}
+	s.MoveToFront("c")
+	s.Delete("a")
+	if want := []string{"c", "b"}; !slices.Equal(s.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", s.Keys(), want)
+	}
+	if k, _ := s.At(1); k != "b" || s.IndexOf("c") != 0 {
+		t.Errorf("At(1) = %s, IndexOf(c) = %d", k, s.IndexOf("c"))
+	}
+	if got := collectKeys(s.Slice(1, 2)); !slices.Equal(got, []string{"b"}) {
+		t.Errorf("Slice(1, 2) = %v", got)
+	}
+
+	if v, loaded := s.GetOrSet("d", 4); loaded || v != 4 {
+		t.Errorf("GetOrSet(d) = %d, %v", v, loaded)
+	}
+	s.Compute("d", func(old int, exists bool) (int, bool) {
+		return old * 10, true
+	})
+	if got := s.Get("d"); got != 40 {
+		t.Errorf("Get(d) = %d, want 40", got)
+	}
+
+	c := s.Clone()
+	c.Set("e", 5)
+	if s.Has("e") || !s.Equal(s.Clone()) || s.Equal(c) {
+		t.Error("Clone() shares entries with the original")
+	}
+	if got := s.Snapshot(); !slices.Equal(got.Keys(), s.Keys()) {
+		t.Errorf("Snapshot() = %v, want %v", got.Keys(), s.Keys())
+	}
+}
+
+func TestSyncMap_Iteration(t *testing.T) {
+	s := NewSync[int, int]()
+	for i := range 5 {
+		s.Set(i, i)
+	}
+
+	// the loop body may modify the map, since it walks a snapshot
+	var keys []int
+	for k := range s.All() {
+		keys = append(keys, k)
+		s.Delete(k + 1)
+		s.Set(k+10, k)
+	}
+	if want := []int{0, 1, 2, 3, 4}; !slices.Equal(keys, want) {
+		t.Errorf("All() = %v, want %v", keys, want)
+	}
+
+	if got := collectKeys(s.Backward()); !slices.Equal(got, []int{14, 13, 12, 11, 10, 0}) {
+		t.Errorf("Backward() = %v", got)
+	}
+	if got := collectKeys(s.From(12)); !slices.Equal(got, []int{12, 13, 14}) {
+		t.Errorf("From(12) = %v", got)
+	}
+	if got := collectKeys(s.Between(10, 12)); !slices.Equal(got, []int{10, 11, 12}) {
+		t.Errorf("Between(10, 12) = %v", got)
+	}
+	if got := collectKeys(s.Between(10, 99)); got != nil {
+		t.Errorf("Between(10, 99) = %v, want empty", got)
+	}
+	if got := collectKeys(s.Between(12, 10)); got != nil {
+		t.Errorf("Between(12, 10) = %v, want empty", got)
+	}
+	if got := slices.Collect(s.KeysSeq()); !slices.Equal(got, s.Keys()) {
+		t.Errorf("KeysSeq() = %v, want %v", got, s.Keys())
+	}
+	if got := slices.Collect(s.ValuesSeq()); !slices.Equal(got, s.Values()) {
+		t.Errorf("ValuesSeq() = %v, want %v", got, s.Values())
+	}
+	for i, e := range s.Enumerate() {
+		if k, _ := s.At(i); k != e.Key {
+			t.Errorf("Enumerate() position %d has key %d, want %d", i, e.Key, k)
+		}
+	}
+
+	// a range that shrinks before the iteration starts is cut short
+	seq := s.Slice(1, 6)
+	s.Truncate(3)
+	if got := collectKeys(seq); !slices.Equal(got, []int{10, 11}) {
+		t.Errorf("Slice(1, 6) after Truncate(3) = %v", got)
+	}
+}
+
+func TestSyncMap_Concurrent(t *testing.T) {
+	s := NewSync[int, int]()
+	var wg sync.WaitGroup
+	for w := range 8 {
+		wg.Go(func() {
+			for i := range 200 {
+				k := w*1000 + i
+				s.Set(k, i)
+				s.Compute(-1, func(old int, _ bool) (int, bool) {
+					return old + 1, true
+				})
+				if i%3 == 0 {
+					s.Delete(k)
+				}
+				if i%50 == 0 {
+					for range s.All() {
+					}
+					if _, err := json.Marshal(s); err != nil {
+						t.Error(err)
+					}
+					if _, err := yaml.Marshal(s); err != nil {
+						t.Error(err)
+					}
+					s.IndexOf(k)
+				}
+			}
+		})
+	}
+	wg.Wait()
+
+	if got := s.Get(-1); got != 8*200 {
+		t.Errorf("Get(-1) = %d, want %d", got, 8*200)
+	}
+	if want := 1 + 8*(200-67); s.Len() != want {
+		t.Errorf("Len() = %d, want %d", s.Len(), want)
+	}
+}
+
+func TestSyncMap_MergeEachOther(t *testing.T) {
+	a, b := NewSync[int, int](), NewSync[int, int]()
+	a.Set(1, 1)
+	b.Set(2, 2)
+	var wg sync.WaitGroup
+	for range 100 {
+		wg.Go(func() { a.Merge(b) })
+		wg.Go(func() { b.Merge(a) })
+	}
+	wg.Wait()
+	a.Merge(a)
+	if !a.EqualUnordered(b) || a.Len() != 2 {
+		t.Errorf("a = %v, b = %v", a.Keys(), b.Keys())
+	}
+}
+
+func TestSyncMap_Encoding(t *testing.T) {
+	s := NewSync[string, int]()
+	s.Set("z", 0)
+	if err := json.Unmarshal([]byte(`{"b":2,"a":1}`), s); err != nil {
+		t.Fatal(err)
+	}
+	b, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"z":0,"b":2,"a":1}`; string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+
+	s = NewSync[string, int]()
+	if err := yaml.Unmarshal([]byte("b: 2\na: 1\n"), s); err != nil {
+		t.Fatal(err)
+	}
+	b, err = yaml.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "b: 2\na: 1\n"; string(b) != want {
+		t.Errorf("yaml.Marshal() = %q, want %q", b, want)
+	}
+}
+
+func TestSyncMap_Sort(t *testing.T) {
+	s := NewSync[string, int]()
+	s.Set("b", 1)
+	s.Set("a", 2)
+	Sort(s)
+	if want := []string{"a", "b"}; !slices.Equal(s.Keys(), want) {
+		t.Errorf("Sort() keys = %v, want %v", s.Keys(), want)
+	}
+	SortParallel(s, func(k1, k2 string) int { return strings.Compare(k2, k1) })
+	if want := []string{"b", "a"}; !slices.Equal(s.Keys(), want) {
+		t.Errorf("SortParallel() keys = %v, want %v", s.Keys(), want)
+	}
+}
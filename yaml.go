@@ -8,7 +8,80 @@ import (
 
 // MarshalYAML implements the yaml.Marshaler interface for Map.
 func (m *Map[K, V]) MarshalYAML() (any, error) {
-	kvNodes := make([]*yaml.Node, 0, len(m.kv)*2)
+	return MarshalYAML(m)
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for Map.
+func (m *Map[K, V]) UnmarshalYAML(n *yaml.Node) error {
+	return UnmarshalYAML(n, m)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface for SortedMap.
+func (s *SortedMap[K, V]) MarshalYAML() (any, error) {
+	return MarshalYAML(s)
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for SortedMap.
+func (s *SortedMap[K, V]) UnmarshalYAML(n *yaml.Node) error {
+	return UnmarshalYAML(n, s)
+}
+
+// MarshalYAML implements the yaml.Marshaler interface for SyncMap, using a snapshot of the entries.
+func (s *SyncMap[K, V]) MarshalYAML() (any, error) {
+	return MarshalYAML(s)
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for SyncMap.
+// The pairs are decoded first and then set under a single lock.
+func (s *SyncMap[K, V]) UnmarshalYAML(n *yaml.Node) error {
+	m := New[K, V]()
+	if err := UnmarshalYAML(n, m); err != nil {
+		return err
+	}
+	s.Merge(m)
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface for COWMap, using the current version.
+func (c *COWMap[K, V]) MarshalYAML() (any, error) {
+	return MarshalYAML(c)
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for COWMap.
+// The pairs are decoded first and then published as a single new version.
+func (c *COWMap[K, V]) UnmarshalYAML(n *yaml.Node) error {
+	m := New[K, V]()
+	if err := UnmarshalYAML(n, m); err != nil {
+		return err
+	}
+	c.Update(func(cur *Map[K, V]) {
+		cur.Merge(m)
+	})
+	return nil
+}
+
+// MarshalYAML implements the yaml.Marshaler interface for ShardedMap, using a consistent cut.
+func (s *ShardedMap[K, V]) MarshalYAML() (any, error) {
+	return MarshalYAML(s)
+}
+
+// UnmarshalYAML implements the yaml.Unmarshaler interface for ShardedMap.
+// The pairs are decoded first and then set one by one.
+func (s *ShardedMap[K, V]) UnmarshalYAML(n *yaml.Node) error {
+	m := New[K, V]()
+	if err := UnmarshalYAML(n, m); err != nil {
+		return err
+	}
+	for k, v := range m.All() {
+		s.Set(k, v)
+	}
+	return nil
+}
+
+// MarshalYAML encodes the entries of m as a YAML mapping node, preserving their order.
+// It helps implementations of OrderedMap to implement yaml.Marshaler.
+func MarshalYAML[K comparable, V any](m OrderedMap[K, V]) (any, error) {
+	kvNodes := make([]*yaml.Node, 0, m.Len()*2)
 	for k, v := range m.All() {
 		keyNode := &yaml.Node{}
 		if err := keyNode.Encode(k); err != nil {
@@ -30,8 +103,9 @@ func (m *Map[K, V]) MarshalYAML() (any, error) {
 	return mapNode, nil
 }
 
-// UnmarshalYAML implements the yaml.Unmarshaler interface for Map.
-func (m *Map[K, V]) UnmarshalYAML(n *yaml.Node) error {
+// UnmarshalYAML decodes a YAML mapping node and sets its pairs in m in order.
+// It helps implementations of OrderedMap to implement yaml.Unmarshaler.
+func UnmarshalYAML[K comparable, V any](n *yaml.Node, m OrderedMap[K, V]) error {
 	if n.Kind != yaml.MappingNode {
 		return errors.New("expected a mapping node")
 	}
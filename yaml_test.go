@@ -326,3 +326,21 @@ func TestMap_YAMLRoundTrip(t *testing.T) {
 		}
 	})
 }
+
+func TestSortedMap_YAML(t *testing.T) {
+	s := NewSorted[string, int]()
+	if err := yaml.Unmarshal([]byte("c: 3\na: 1\nb: 2\n"), s); err != nil {
+		t.Fatalf("UnmarshalYAML failed: %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !slices.Equal(s.Keys(), want) {
+		t.Errorf("Keys() = %v, want %v", s.Keys(), want)
+	}
+
+	b, err := yaml.Marshal(s)
+	if err != nil {
+		t.Fatalf("MarshalYAML failed: %v", err)
+	}
+	if expected := "a: 1\nb: 2\nc: 3\n"; string(b) != expected {
+		t.Errorf("MarshalYAML = %q, want %q", string(b), expected)
+	}
+}